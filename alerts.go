@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Default temperatures in °C at or beyond which an alert is raised
+const (
+	defaultExtremeCold = -20
+	defaultExtremeHeat = 35
+)
+
+// alertThresholdLimit bounds the configurable thresholds, in °C
+const alertThresholdLimit = 100
+
+// AlertConfig sets when temperature alerts are raised
+type AlertConfig struct {
+	// ExtremeCold raises an alert at or below this temperature in °C
+	ExtremeCold float64 `json:"extremeCold"`
+	// ExtremeHeat raises an alert at or above this temperature in °C
+	ExtremeHeat float64 `json:"extremeHeat"`
+}
+
+// validateAlertConfig checks that the thresholds are plausible and ordered
+func validateAlertConfig(config *AlertConfig) error {
+	for _, threshold := range []float64{config.ExtremeCold, config.ExtremeHeat} {
+		if threshold < -alertThresholdLimit || threshold > alertThresholdLimit {
+			return fmt.Errorf("alert thresholds must be between -%d and %d °C", alertThresholdLimit, alertThresholdLimit)
+		}
+	}
+	if config.ExtremeCold >= config.ExtremeHeat {
+		return fmt.Errorf("alerts.extremeCold must be below alerts.extremeHeat")
+	}
+	return nil
+}
+
+// WeatherAlert is a severe weather warning for the current conditions
+type WeatherAlert struct {
+	Kind         string `json:"kind"`
+	Message      string `json:"message"`
+	Acknowledged bool   `json:"acknowledged"`
+}
+
+// alertState tracks the current alert and whether the user has seen it
+type alertState struct {
+	mu          sync.Mutex
+	current     *WeatherAlert
+	changedFunc func(unacknowledged bool)
+}
+
+// weatherAlertKind returns the kind of alert the weather warrants, or ""
+func weatherAlertKind(weather *WeatherData, config *AlertConfig) string {
+	switch conditionCategory(weather.WeatherCode) {
+	case ConditionThunderstorm, ConditionHail:
+		return "thunderstorm"
+	case ConditionFreezingRain:
+		return "freezingRain"
+	}
+
+	switch {
+	case weather.Temperature <= config.ExtremeCold:
+		return "extremeCold"
+	case weather.Temperature >= config.ExtremeHeat:
+		return "extremeHeat"
+	}
+	return ""
+}
+
+// SetAlertChangedFunc sets the function called when an alert is raised,
+// cleared or acknowledged
+func (a *App) SetAlertChangedFunc(changedFunc func(unacknowledged bool)) {
+	a.alerts.mu.Lock()
+	a.alerts.changedFunc = changedFunc
+	a.alerts.mu.Unlock()
+}
+
+// updateAlert raises or clears the alert for new weather. An alert of the
+// same kind as the current one keeps its acknowledgement.
+func (a *App) updateAlert(weather *WeatherData, config *AlertConfig) {
+	kind := weatherAlertKind(weather, config)
+
+	a.alerts.mu.Lock()
+	switch {
+	case kind == "":
+		a.alerts.current = nil
+	case a.alerts.current == nil || a.alerts.current.Kind != kind:
+		a.alerts.current = &WeatherAlert{Kind: kind}
+	}
+	a.alerts.mu.Unlock()
+
+	a.notifyAlertChanged()
+}
+
+// AcknowledgeAlert marks the current alert as seen, which stops the tray
+// icon from blinking
+func (a *App) AcknowledgeAlert() {
+	a.alerts.mu.Lock()
+	if a.alerts.current != nil {
+		a.alerts.current.Acknowledged = true
+	}
+	a.alerts.mu.Unlock()
+
+	a.notifyAlertChanged()
+}
+
+// GetActiveAlert returns the current alert, or nil when there is none
+func (a *App) GetActiveAlert() *WeatherAlert {
+	// Load the language first so the lock is not held during file IO
+	lang := a.currentLanguage()
+
+	a.alerts.mu.Lock()
+	defer a.alerts.mu.Unlock()
+
+	if a.alerts.current == nil {
+		return nil
+	}
+	alert := *a.alerts.current
+	alert.Message = translate(lang, "alert."+alert.Kind)
+	return &alert
+}
+
+// notifyAlertChanged reports whether an unacknowledged alert is active
+func (a *App) notifyAlertChanged() {
+	a.alerts.mu.Lock()
+	unacknowledged := a.alerts.current != nil && !a.alerts.current.Acknowledged
+	changedFunc := a.alerts.changedFunc
+	a.alerts.mu.Unlock()
+
+	if changedFunc != nil {
+		changedFunc(unacknowledged)
+	}
+}
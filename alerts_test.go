@@ -0,0 +1,96 @@
+package main
+
+import "testing"
+
+func TestWeatherAlertKind(t *testing.T) {
+	defaults := (&App{}).GetDefaultConfig().Alerts
+	custom := AlertConfig{ExtremeCold: -5, ExtremeHeat: 28}
+
+	tests := []struct {
+		name    string
+		weather WeatherData
+		config  AlertConfig
+		want    string
+	}{
+		{"mild", WeatherData{Temperature: 12}, defaults, ""},
+		{"at cold threshold", WeatherData{Temperature: -20}, defaults, "extremeCold"},
+		{"just above cold threshold", WeatherData{Temperature: -19.9}, defaults, ""},
+		{"at heat threshold", WeatherData{Temperature: 35}, defaults, "extremeHeat"},
+		{"just below heat threshold", WeatherData{Temperature: 34.9}, defaults, ""},
+		{"custom cold", WeatherData{Temperature: -6}, custom, "extremeCold"},
+		{"custom heat", WeatherData{Temperature: 30}, custom, "extremeHeat"},
+		{"thunderstorm wins over heat", WeatherData{Temperature: 40, WeatherCode: 95}, defaults, "thunderstorm"},
+		{"freezing rain", WeatherData{Temperature: -1, WeatherCode: 66}, defaults, "freezingRain"},
+	}
+
+	for _, tt := range tests {
+		if got := weatherAlertKind(&tt.weather, &tt.config); got != tt.want {
+			t.Errorf("%s: weatherAlertKind = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateAlertTransitions(t *testing.T) {
+	config := (&App{}).GetDefaultConfig().Alerts
+	app := &App{}
+
+	var notified []bool
+	app.SetAlertChangedFunc(func(unacknowledged bool) {
+		notified = append(notified, unacknowledged)
+	})
+	kind := func() string {
+		if alert := app.GetActiveAlert(); alert != nil {
+			return alert.Kind
+		}
+		return ""
+	}
+
+	app.updateAlert(&WeatherData{Temperature: -25}, &config)
+	if kind() != "extremeCold" {
+		t.Fatalf("alert = %q, want extremeCold", kind())
+	}
+
+	app.AcknowledgeAlert()
+	app.updateAlert(&WeatherData{Temperature: -22}, &config)
+	if alert := app.GetActiveAlert(); alert == nil || !alert.Acknowledged {
+		t.Error("acknowledgement lost for an alert of the same kind")
+	}
+
+	app.updateAlert(&WeatherData{Temperature: -22, WeatherCode: 95}, &config)
+	if alert := app.GetActiveAlert(); alert == nil || alert.Kind != "thunderstorm" || alert.Acknowledged {
+		t.Errorf("new alert kind = %+v, want unacknowledged thunderstorm", alert)
+	}
+
+	app.updateAlert(&WeatherData{Temperature: 10}, &config)
+	if kind() != "" {
+		t.Errorf("alert = %q after the weather cleared", kind())
+	}
+
+	want := []bool{true, false, false, true, false}
+	if len(notified) != len(want) {
+		t.Fatalf("notifications = %v, want %v", notified, want)
+	}
+	for i := range want {
+		if notified[i] != want[i] {
+			t.Fatalf("notifications = %v, want %v", notified, want)
+		}
+	}
+}
+
+func TestValidateAlertConfig(t *testing.T) {
+	tests := []struct {
+		config AlertConfig
+		valid  bool
+	}{
+		{AlertConfig{ExtremeCold: -20, ExtremeHeat: 35}, true},
+		{AlertConfig{ExtremeCold: 30, ExtremeHeat: 20}, false},
+		{AlertConfig{ExtremeCold: 10, ExtremeHeat: 10}, false},
+		{AlertConfig{ExtremeCold: -150, ExtremeHeat: 35}, false},
+	}
+
+	for _, tt := range tests {
+		if err := validateAlertConfig(&tt.config); (err == nil) != tt.valid {
+			t.Errorf("validateAlertConfig(%+v) = %v, want valid %v", tt.config, err, tt.valid)
+		}
+	}
+}
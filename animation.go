@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// defaultAnimationFPS is the tray animation frame rate when not configured
+const defaultAnimationFPS = 8
+
+// maxAnimationFPS limits how often the tray icon is replaced
+const maxAnimationFPS = 30
+
+// Animation cycle lengths
+const (
+	pulsePeriod = 1200 * time.Millisecond // refresh ring
+	blinkPeriod = 1000 * time.Millisecond // alert badge
+)
+
+// trayAnimator cycles tray icon frames while a refresh is in flight or an
+// alert is unacknowledged. The setters only record state and wake the
+// animation goroutine, so callers such as WeatherService never block on
+// rendering or on the tray.
+type trayAnimator struct {
+	mu         sync.Mutex
+	base       *image.RGBA
+	template   bool
+	refreshing bool
+	alerting   bool
+	fps        int
+
+	setIcon func(data []byte, template bool)
+	now     func() time.Time
+	after   func(time.Duration) <-chan time.Time
+	wake    chan struct{}
+	quit    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// newTrayAnimator creates an animator that hands encoded frames to setIcon
+func newTrayAnimator(setIcon func(data []byte, template bool)) *trayAnimator {
+	return &trayAnimator{
+		fps:     defaultAnimationFPS,
+		setIcon: setIcon,
+		now:     time.Now,
+		after:   time.After,
+		wake:    make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start runs the animation loop until Stop is called
+func (t *trayAnimator) Start() {
+	go t.run()
+}
+
+// Stop ends the animation loop and waits for it to exit
+func (t *trayAnimator) Stop() {
+	t.once.Do(func() {
+		close(t.quit)
+	})
+	<-t.done
+}
+
+// SetBase sets the static icon, which frames are drawn on top of. It is
+// shown as soon as no animation is running.
+func (t *trayAnimator) SetBase(img *image.RGBA, template bool) {
+	t.mu.Lock()
+	t.base = img
+	t.template = template
+	t.mu.Unlock()
+	t.signal()
+}
+
+// SetRefreshing starts or stops the refresh ring
+func (t *trayAnimator) SetRefreshing(refreshing bool) {
+	t.mu.Lock()
+	t.refreshing = refreshing
+	t.mu.Unlock()
+	t.signal()
+}
+
+// SetAlert starts or stops the alert badge
+func (t *trayAnimator) SetAlert(alerting bool) {
+	t.mu.Lock()
+	t.alerting = alerting
+	t.mu.Unlock()
+	t.signal()
+}
+
+// SetFPS sets the frame rate; 0 disables animation
+func (t *trayAnimator) SetFPS(fps int) {
+	if fps > maxAnimationFPS {
+		fps = maxAnimationFPS
+	}
+	t.mu.Lock()
+	t.fps = fps
+	t.mu.Unlock()
+	t.signal()
+}
+
+// signal wakes the animation loop without blocking
+func (t *trayAnimator) signal() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// active reports whether a frame should be animated and at what rate
+func (t *trayAnimator) active() (bool, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.fps <= 0 || t.base == nil || !t.refreshing && !t.alerting {
+		return false, 0
+	}
+	return true, time.Second / time.Duration(t.fps)
+}
+
+// run waits for work, then draws frames until the animation is idle again
+func (t *trayAnimator) run() {
+	defer close(t.done)
+
+	for {
+		select {
+		case <-t.quit:
+			return
+		case <-t.wake:
+		}
+
+		start := t.now()
+		for {
+			ok, interval := t.active()
+			if !ok {
+				break
+			}
+			t.drawFrame(t.now().Sub(start))
+
+			select {
+			case <-t.quit:
+				return
+			case <-t.wake:
+			case <-t.after(interval):
+			}
+		}
+
+		// Show the static icon once idle
+		t.drawFrame(-1)
+	}
+}
+
+// drawFrame renders and shows the frame at elapsed time into the
+// animation. A negative elapsed time draws the static icon.
+func (t *trayAnimator) drawFrame(elapsed time.Duration) {
+	t.mu.Lock()
+	base, template := t.base, t.template
+	refreshing, alerting := t.refreshing, t.alerting
+	t.mu.Unlock()
+
+	if base == nil {
+		return
+	}
+
+	frame := renderFrame(base, template, refreshing, alerting, elapsed)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, frame); err != nil {
+		log.Printf("Failed to encode tray animation frame: %v", err)
+		return
+	}
+	t.setIcon(buf.Bytes(), template)
+}
+
+// renderFrame draws the animation on a copy of base at elapsed time into
+// the animation. A negative elapsed time returns the static icon.
+func renderFrame(base *image.RGBA, template, refreshing, alerting bool, elapsed time.Duration) *image.RGBA {
+	frame := image.NewRGBA(base.Bounds())
+	draw.Draw(frame, frame.Bounds(), base, base.Bounds().Min, draw.Src)
+
+	if elapsed >= 0 {
+		if refreshing {
+			drawPulseRing(frame, phase(elapsed, pulsePeriod), template)
+		}
+		if alerting && phase(elapsed, blinkPeriod) < 0.5 {
+			drawAlertBadge(frame, template)
+		}
+	}
+	return frame
+}
+
+// phase returns how far elapsed is into the current cycle, from 0 to 1
+func phase(elapsed, period time.Duration) float64 {
+	return float64(elapsed%period) / float64(period)
+}
+
+// drawPulseRing draws a ring that fades in and out around the icon edge
+func drawPulseRing(img *image.RGBA, phase float64, template bool) {
+	size := float64(img.Bounds().Dx())
+	alpha := 0.35 + 0.65*(0.5-0.5*math.Cos(2*math.Pi*phase))
+
+	ring := color.RGBA{255, 255, 255, 255}
+	if template {
+		ring = color.RGBA{0, 0, 0, 255}
+	}
+	width := math.Max(size/12, 1)
+	strokeCircle(img, size/2, size/2, size/2-width/2, width, ring, alpha)
+}
+
+// drawAlertBadge draws a warning dot in the top-right corner
+func drawAlertBadge(img *image.RGBA, template bool) {
+	size := float64(img.Bounds().Dx())
+	radius := size / 5
+	cx, cy := size-radius, radius
+
+	badge := color.RGBA{229, 57, 53, 255}
+	if template {
+		badge = color.RGBA{0, 0, 0, 255}
+	}
+	fillCircle(img, cx, cy, radius+math.Max(size/32, 1), color.RGBA{255, 255, 255, 255})
+	fillCircle(img, cx, cy, radius, badge)
+}
+
+// strokeCircle draws an anti-aliased ring of the given width, scaled by
+// opacity
+func strokeCircle(img *image.RGBA, cx, cy, radius, width float64, c color.Color, opacity float64) {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			dx := float64(x) + 0.5 - cx
+			dy := float64(y) + 0.5 - cy
+			coverage := width/2 - math.Abs(math.Sqrt(dx*dx+dy*dy)-radius) + 0.5
+			if coverage <= 0 {
+				continue
+			}
+			blendPixel(img, x, y, c, math.Min(coverage, 1)*opacity)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAnimationClock drives a trayAnimator without real time passing
+type fakeAnimationClock struct {
+	mu        sync.Mutex
+	now       time.Time
+	ticks     chan time.Time
+	intervals chan time.Duration
+}
+
+func newFakeAnimationClock() *fakeAnimationClock {
+	return &fakeAnimationClock{
+		now:       time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		ticks:     make(chan time.Time),
+		intervals: make(chan time.Duration, 16),
+	}
+}
+
+func (c *fakeAnimationClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeAnimationClock) After(d time.Duration) <-chan time.Time {
+	c.intervals <- d
+	return c.ticks
+}
+
+// tick advances the clock and delivers the tick the animator waits for
+func (c *fakeAnimationClock) tick(t *testing.T, d time.Duration) {
+	t.Helper()
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	c.mu.Unlock()
+
+	select {
+	case c.ticks <- now:
+	case <-time.After(time.Second):
+		t.Fatal("animator is not waiting for a tick")
+	}
+}
+
+// newTestAnimator returns an animator on a fake clock and the channel its
+// frames are delivered on
+func newTestAnimator(clock *fakeAnimationClock) (*trayAnimator, chan []byte) {
+	frames := make(chan []byte, 16)
+	animator := newTrayAnimator(func(data []byte, template bool) {
+		frames <- data
+	})
+	animator.now = clock.Now
+	animator.after = clock.After
+	return animator, frames
+}
+
+// testAnimationBase returns a plain icon to animate
+func testAnimationBase() *image.RGBA {
+	base := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	draw.Draw(base, base.Bounds(), image.NewUniform(color.RGBA{30, 136, 229, 255}), image.Point{}, draw.Src)
+	return base
+}
+
+// expectFrame waits for the next frame and compares it with the frame
+// rendered at elapsed
+func expectFrame(t *testing.T, frames <-chan []byte, base *image.RGBA, refreshing, alerting bool, elapsed time.Duration) {
+	t.Helper()
+
+	var want bytes.Buffer
+	if err := png.Encode(&want, renderFrame(base, false, refreshing, alerting, elapsed)); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-frames:
+		if !bytes.Equal(got, want.Bytes()) {
+			t.Errorf("frame does not match the frame at %v", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no frame for %v", elapsed)
+	}
+}
+
+func TestTrayAnimatorStepsFrames(t *testing.T) {
+	clock := newFakeAnimationClock()
+	animator, frames := newTestAnimator(clock)
+	base := testAnimationBase()
+
+	animator.SetFPS(10)
+	animator.SetBase(base, false)
+	animator.Start()
+	defer animator.Stop()
+	expectFrame(t, frames, base, false, false, -1)
+
+	animator.SetRefreshing(true)
+	expectFrame(t, frames, base, true, false, 0)
+	if interval := <-clock.intervals; interval != 100*time.Millisecond {
+		t.Errorf("interval = %v at 10 fps, want 100ms", interval)
+	}
+
+	clock.tick(t, 300*time.Millisecond)
+	expectFrame(t, frames, base, true, false, 300*time.Millisecond)
+	<-clock.intervals
+
+	// The alert badge joins the ring on the next frame
+	animator.SetAlert(true)
+	expectFrame(t, frames, base, true, true, 300*time.Millisecond)
+	<-clock.intervals
+
+	clock.tick(t, 600*time.Millisecond)
+	expectFrame(t, frames, base, true, true, 900*time.Millisecond)
+	<-clock.intervals
+
+	// The static icon is shown once idle
+	animator.SetRefreshing(false)
+	animator.SetAlert(false)
+	var static bytes.Buffer
+	if err := png.Encode(&static, base); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case frame := <-frames:
+			if bytes.Equal(frame, static.Bytes()) {
+				return
+			}
+		case <-deadline:
+			t.Fatal("static icon not shown after the animation stopped")
+		}
+	}
+}
+
+func TestTrayAnimatorDisabled(t *testing.T) {
+	clock := newFakeAnimationClock()
+	animator, frames := newTestAnimator(clock)
+	base := testAnimationBase()
+
+	animator.SetFPS(0)
+	animator.SetBase(base, false)
+	animator.SetRefreshing(true)
+	animator.Start()
+	defer animator.Stop()
+
+	expectFrame(t, frames, base, false, false, -1)
+	select {
+	case interval := <-clock.intervals:
+		t.Errorf("animator waited %v for a frame at 0 fps", interval)
+	default:
+	}
+}
+
+func TestTrayAnimatorSettersDoNotBlock(t *testing.T) {
+	clock := newFakeAnimationClock()
+	release := make(chan struct{})
+	drawing := make(chan struct{}, 1)
+	animator := newTrayAnimator(func(data []byte, template bool) {
+		select {
+		case drawing <- struct{}{}:
+		default:
+		}
+		<-release
+	})
+	animator.now = clock.Now
+	animator.after = clock.After
+
+	animator.SetBase(testAnimationBase(), false)
+	animator.Start()
+	<-drawing
+
+	// The loop is stuck handing a frame to the tray
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			animator.SetRefreshing(i%2 == 0)
+			animator.SetAlert(i%3 == 0)
+			animator.SetFPS(i % maxAnimationFPS)
+			animator.SetBase(testAnimationBase(), i%2 == 0)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("setters blocked while a frame was being shown")
+	}
+
+	close(release)
+	animator.Stop()
+}
+
+func TestTrayAnimatorStop(t *testing.T) {
+	clock := newFakeAnimationClock()
+	animator, frames := newTestAnimator(clock)
+	base := testAnimationBase()
+
+	animator.SetBase(base, false)
+	animator.SetAlert(true)
+	animator.Start()
+	expectFrame(t, frames, base, false, true, 0)
+	<-clock.intervals
+
+	// Stop interrupts the wait for the next frame and can be called again
+	stopped := make(chan struct{})
+	go func() {
+		animator.Stop()
+		animator.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not end the animation loop")
+	}
+
+	select {
+	case <-frames:
+		t.Error("frame drawn after Stop")
+	default:
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Release asset formats
+const (
+	AssetFormatAppImage = "appimage"
+	AssetFormatDeb      = "deb"
+	AssetFormatRPM      = "rpm"
+	AssetFormatTarGz    = "tar.gz"
+	AssetFormatZip      = "zip"
+	AssetFormatDMG      = "dmg"
+	AssetFormatPKG      = "pkg"
+	AssetFormatMSI      = "msi"
+	AssetFormatExe      = "exe"
+	AssetFormatSetup    = "setup"  // a Windows installer .exe
+	AssetFormatBinary   = "binary" // a bare executable without extension
+)
+
+// installerFormats are handed to the platform installer instead of
+// replacing the binary
+var installerFormats = []string{AssetFormatMSI, AssetFormatSetup, AssetFormatPKG, AssetFormatDMG, AssetFormatDeb, AssetFormatRPM}
+
+// setupNameMarkers identify .exe assets that are installers rather than
+// the app itself, e.g. "myWeatherApp-amd64-installer.exe" or "setup.exe"
+var setupNameMarkers = []string{"installer", "setup"}
+
+// assetArchUniversal marks macOS universal binaries, which run on any Mac
+const assetArchUniversal = "universal"
+
+// assetFormatSuffixes maps file name suffixes to formats. Longer suffixes
+// come first so ".tar.gz" wins over ".gz".
+var assetFormatSuffixes = []struct {
+	suffix string
+	format string
+}{
+	{".tar.gz", AssetFormatTarGz},
+	{".tgz", AssetFormatTarGz},
+	{".appimage", AssetFormatAppImage},
+	{".deb", AssetFormatDeb},
+	{".rpm", AssetFormatRPM},
+	{".zip", AssetFormatZip},
+	{".dmg", AssetFormatDMG},
+	{".pkg", AssetFormatPKG},
+	{".msi", AssetFormatMSI},
+	{".exe", AssetFormatExe},
+}
+
+// nonInstallableSuffixes are signatures, checksums and metadata published
+// next to the downloads
+var nonInstallableSuffixes = []string{
+	".sig", ".minisig", ".asc", ".pem", ".sha256", ".sha512", ".txt", ".json", ".sbom", ".spdx", ".md",
+}
+
+// assetOSAliases maps name tokens to GOOS values
+var assetOSAliases = map[string]string{
+	"linux":   "linux",
+	"darwin":  "darwin",
+	"macos":   "darwin",
+	"mac":     "darwin",
+	"osx":     "darwin",
+	"windows": "windows",
+	"win":     "windows",
+	"win64":   "windows",
+}
+
+// assetArchAliases maps name tokens to GOARCH values. amd64 micro-
+// architecture levels such as amd64v3 are matched separately.
+var assetArchAliases = map[string]string{
+	"amd64":     "amd64",
+	"x86_64":    "amd64",
+	"x64":       "amd64",
+	"arm64":     "arm64",
+	"aarch64":   "arm64",
+	"386":       "386",
+	"i386":      "386",
+	"i686":      "386",
+	"x86":       "386",
+	"armv7":     "arm",
+	"armhf":     "arm",
+	"universal": assetArchUniversal,
+}
+
+// defaultAssetFormats lists the preferred formats per OS. Formats the
+// updater can install in place come before installer packages.
+var defaultAssetFormats = map[string][]string{
+	"linux":   {AssetFormatAppImage, AssetFormatTarGz, AssetFormatBinary, AssetFormatDeb, AssetFormatRPM},
+	"darwin":  {AssetFormatZip, AssetFormatTarGz, AssetFormatBinary, AssetFormatDMG, AssetFormatPKG},
+	"windows": {AssetFormatZip, AssetFormatExe, AssetFormatMSI, AssetFormatSetup},
+}
+
+// assetTarget is the platform and format of a release asset
+type assetTarget struct {
+	OS     string
+	Arch   string
+	Level  int // amd64 micro-architecture level, 1 for plain amd64
+	Format string
+}
+
+// platform is what the running binary needs
+type platform struct {
+	OS    string
+	Arch  string
+	Level int
+}
+
+// currentPlatform describes the running binary
+func currentPlatform() platform {
+	return platform{OS: runtime.GOOS, Arch: runtime.GOARCH, Level: amd64Level()}
+}
+
+// amd64Level returns the GOAMD64 level the binary was built for. The CPU
+// supports at least that level, so assets built for it are safe to run.
+func amd64Level() int {
+	if runtime.GOARCH != "amd64" {
+		return 0
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "GOAMD64" && len(setting.Value) == 2 && setting.Value[1] >= '1' && setting.Value[1] <= '4' {
+				return int(setting.Value[1] - '0')
+			}
+		}
+	}
+	return 1
+}
+
+// assetFormat returns the format of an asset from its file name, or "" for
+// files that are not installable such as signatures
+func assetFormat(name string) string {
+	name = strings.ToLower(name)
+	for _, suffix := range nonInstallableSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return ""
+		}
+	}
+	for _, s := range assetFormatSuffixes {
+		if !strings.HasSuffix(name, s.suffix) {
+			continue
+		}
+		if s.format == AssetFormatExe && isSetupName(strings.TrimSuffix(name, s.suffix)) {
+			return AssetFormatSetup
+		}
+		return s.format
+	}
+	// Any other extension, e.g. ".gz" or ".apk", is not ours to install,
+	// while a trailing version number such as "-1.2.0" is not an extension
+	if dot := strings.LastIndex(name, "."); dot >= 0 && isLetters(name[dot+1:]) {
+		return ""
+	}
+	return AssetFormatBinary
+}
+
+// isSetupName reports whether a lower-case .exe name without its extension
+// names an installer
+func isSetupName(name string) bool {
+	for _, marker := range setupNameMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInstallerFormat reports whether assets of format are installed by the
+// platform installer
+func isInstallerFormat(format string) bool {
+	return containsString(installerFormats, format)
+}
+
+// parseAssetTarget reads the OS, architecture and format of an asset.
+// Explicit manifest fields win; otherwise they come from the name, split
+// into tokens at "-", "_" and ".", such as "myWeatherApp-linux-amd64v3.tar.gz".
+func parseAssetTarget(asset ReleaseAsset) (assetTarget, bool) {
+	target := assetTarget{
+		OS:     strings.ToLower(asset.OS),
+		Arch:   strings.ToLower(asset.Arch),
+		Format: strings.ToLower(asset.Format),
+	}
+	if target.Format == "" {
+		target.Format = assetFormat(asset.Name)
+	}
+	if target.Format == "" {
+		return assetTarget{}, false
+	}
+
+	// x86_64 would otherwise split into "x86" and "64"
+	name := strings.ToLower(asset.Name)
+	name = strings.NewReplacer("x86_64", "amd64", "x86-64", "amd64").Replace(name)
+	tokens := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+	for i, token := range tokens {
+		if target.OS == "" {
+			if goos, ok := assetOSAliases[token]; ok {
+				target.OS = goos
+				continue
+			}
+		}
+		if target.Arch == "" {
+			// The level may be a separate token, as in "amd64_v3"
+			if token == "amd64" && i+1 < len(tokens) {
+				token += tokens[i+1]
+			}
+			if level, ok := amd64LevelToken(token); ok {
+				target.Arch = "amd64"
+				target.Level = level
+			} else if goarch, ok := assetArchAliases[tokens[i]]; ok {
+				target.Arch = goarch
+			}
+		}
+	}
+
+	if level, ok := amd64LevelToken(target.Arch); ok {
+		target.Arch, target.Level = "amd64", level
+	}
+	if goarch, ok := assetArchAliases[target.Arch]; ok {
+		target.Arch = goarch
+	}
+	if goos, ok := assetOSAliases[target.OS]; ok {
+		target.OS = goos
+	}
+	if target.Arch == "amd64" && target.Level == 0 {
+		target.Level = 1
+	}
+
+	// Installer packages name the OS by their format
+	switch target.Format {
+	case AssetFormatAppImage, AssetFormatDeb, AssetFormatRPM:
+		if target.OS == "" {
+			target.OS = "linux"
+		}
+	case AssetFormatDMG, AssetFormatPKG:
+		if target.OS == "" {
+			target.OS = "darwin"
+		}
+	case AssetFormatMSI, AssetFormatExe, AssetFormatSetup:
+		if target.OS == "" {
+			target.OS = "windows"
+		}
+	}
+
+	return target, target.OS != "" && target.Arch != ""
+}
+
+// amd64LevelToken parses tokens such as "amd64v3"
+func amd64LevelToken(token string) (int, bool) {
+	rest, ok := strings.CutPrefix(token, "amd64v")
+	if !ok || len(rest) != 1 || rest[0] < '1' || rest[0] > '4' {
+		return 0, false
+	}
+	return int(rest[0] - '0'), true
+}
+
+// matches reports whether the asset runs on p, and how well: 2 for the
+// exact architecture, 1 for a universal binary
+func (t assetTarget) matches(p platform) int {
+	if t.OS != p.OS {
+		return 0
+	}
+	switch {
+	case t.Arch == p.Arch && (t.Arch != "amd64" || t.Level <= p.Level):
+		return 2
+	case t.Arch == assetArchUniversal && p.OS == "darwin":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// selectAsset picks the asset to download for p. Assets are ranked by the
+// position of their format in formats, then by architecture fit, then by
+// the highest supported amd64 level. Formats not listed are never chosen.
+func selectAsset(assets []ReleaseAsset, p platform, formats []string) (ReleaseAsset, bool) {
+	var best ReleaseAsset
+	var bestTarget assetTarget
+	bestRank, bestFit := -1, 0
+
+	for _, asset := range assets {
+		target, ok := parseAssetTarget(asset)
+		if !ok {
+			continue
+		}
+		fit := target.matches(p)
+		if fit == 0 {
+			continue
+		}
+		rank := indexOf(formats, target.Format)
+		if rank < 0 {
+			continue
+		}
+
+		better := bestRank < 0 ||
+			rank < bestRank ||
+			rank == bestRank && fit > bestFit ||
+			rank == bestRank && fit == bestFit && target.Level > bestTarget.Level
+		if better {
+			best, bestTarget, bestRank, bestFit = asset, target, rank, fit
+		}
+	}
+
+	return best, bestRank >= 0
+}
+
+// isAssetFormat reports whether format is a known asset format
+func isAssetFormat(format string) bool {
+	if format == AssetFormatBinary || format == AssetFormatSetup {
+		return true
+	}
+	for _, s := range assetFormatSuffixes {
+		if s.format == format {
+			return true
+		}
+	}
+	return false
+}
+
+// assetFormats returns the format preference for goos, from the config or
+// the platform default
+func assetFormats(config *UpdateConfig, goos string) []string {
+	if len(config.AssetFormats) > 0 {
+		return config.AssetFormats
+	}
+	return defaultAssetFormats[goos]
+}
+
+// isLetters reports whether s is a non-empty string of ASCII letters
+func isLetters(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// indexOf returns the position of s in values, or -1
+func indexOf(values []string, s string) int {
+	for i, value := range values {
+		if value == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// githubReleaseAssets is a typical GitHub release with builds for every
+// platform, installers and release metadata
+var githubReleaseAssets = []string{
+	"checksums.txt",
+	"checksums.txt.minisig",
+	"myWeatherApp-darwin-arm64.zip",
+	"myWeatherApp-darwin-universal.dmg",
+	"myWeatherApp-darwin-universal.zip",
+	"myWeatherApp-linux-amd64.AppImage",
+	"myWeatherApp-linux-amd64.tar.gz",
+	"myWeatherApp-linux-amd64v3.tar.gz",
+	"myWeatherApp-linux-arm64.tar.gz",
+	"myweatherapp_1.2.0_amd64.deb",
+	"myweatherapp-1.2.0-1.x86_64.rpm",
+	"myWeatherApp-amd64-installer.exe",
+	"myWeatherApp-windows-amd64.exe",
+	"myWeatherApp-windows-amd64.msi",
+	"myWeatherApp-windows-arm64-setup.exe",
+	"myWeatherApp-windows-arm64.exe",
+	"sbom.spdx.json",
+}
+
+// workflowReleaseAssets are the files .github/workflows/release.yml
+// publishes
+var workflowReleaseAssets = []string{
+	"SHA256SUMS",
+	"SHA256SUMS.minisig",
+	"myWeatherApp-darwin-arm64.tar.gz",
+	"myWeatherApp-linux-amd64.tar.gz",
+	"myWeatherApp-windows-amd64.zip",
+}
+
+// releaseAssets turns asset names into release assets
+func releaseAssets(names []string) []ReleaseAsset {
+	assets := make([]ReleaseAsset, len(names))
+	for i, name := range names {
+		assets[i] = ReleaseAsset{Name: name, URL: "https://example.com/" + name}
+	}
+	return assets
+}
+
+func TestAssetFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"myWeatherApp-windows-amd64.exe", AssetFormatExe},
+		{"myWeatherApp.exe", AssetFormatExe},
+		{"myWeatherApp-amd64-installer.exe", AssetFormatSetup},
+		{"myWeatherApp-Setup-1.2.0.exe", AssetFormatSetup},
+		{"setup.exe", AssetFormatSetup},
+		{"myWeatherAppSetup-x64.EXE", AssetFormatSetup},
+		{"myWeatherApp-windows-amd64.msi", AssetFormatMSI},
+		{"myWeatherApp-linux-amd64.AppImage", AssetFormatAppImage},
+		{"myWeatherApp-linux-amd64.tar.gz", AssetFormatTarGz},
+		{"myWeatherApp-linux-amd64-1.2.0", AssetFormatBinary},
+		{"myWeatherApp-linux-amd64.tar.gz.sha256", ""},
+		{"checksums.txt.minisig", ""},
+		{"myWeatherApp.apk", ""},
+	}
+
+	for _, tt := range tests {
+		if got := assetFormat(tt.name); got != tt.want {
+			t.Errorf("assetFormat(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSelectAsset(t *testing.T) {
+	windowsInstallersOnly := []string{
+		"checksums.txt",
+		"myWeatherApp-amd64-installer.exe",
+		"myWeatherApp-windows-amd64.msi",
+	}
+
+	tests := []struct {
+		name     string
+		assets   []string
+		platform platform
+		formats  []string
+		want     string
+	}{
+		{"windows amd64", githubReleaseAssets, platform{OS: "windows", Arch: "amd64", Level: 1}, nil, "myWeatherApp-windows-amd64.exe"},
+		{"windows arm64", githubReleaseAssets, platform{OS: "windows", Arch: "arm64"}, nil, "myWeatherApp-windows-arm64.exe"},
+		{"windows prefers msi over setup", windowsInstallersOnly, platform{OS: "windows", Arch: "amd64", Level: 1}, nil, "myWeatherApp-windows-amd64.msi"},
+		{"windows setup preferred", windowsInstallersOnly, platform{OS: "windows", Arch: "amd64", Level: 1}, []string{AssetFormatSetup}, "myWeatherApp-amd64-installer.exe"},
+		{"windows exe only", githubReleaseAssets, platform{OS: "windows", Arch: "amd64", Level: 1}, []string{AssetFormatExe}, "myWeatherApp-windows-amd64.exe"},
+		{"linux amd64", githubReleaseAssets, platform{OS: "linux", Arch: "amd64", Level: 1}, nil, "myWeatherApp-linux-amd64.AppImage"},
+		{"linux amd64v3 tarball", githubReleaseAssets, platform{OS: "linux", Arch: "amd64", Level: 3}, []string{AssetFormatTarGz}, "myWeatherApp-linux-amd64v3.tar.gz"},
+		{"linux amd64v1 skips v3", githubReleaseAssets, platform{OS: "linux", Arch: "amd64", Level: 1}, []string{AssetFormatTarGz}, "myWeatherApp-linux-amd64.tar.gz"},
+		{"linux deb", githubReleaseAssets, platform{OS: "linux", Arch: "amd64", Level: 1}, []string{AssetFormatDeb}, "myweatherapp_1.2.0_amd64.deb"},
+		{"linux rpm", githubReleaseAssets, platform{OS: "linux", Arch: "amd64", Level: 1}, []string{AssetFormatRPM}, "myweatherapp-1.2.0-1.x86_64.rpm"},
+		{"darwin arm64", githubReleaseAssets, platform{OS: "darwin", Arch: "arm64"}, nil, "myWeatherApp-darwin-arm64.zip"},
+		{"darwin amd64 universal", githubReleaseAssets, platform{OS: "darwin", Arch: "amd64", Level: 1}, nil, "myWeatherApp-darwin-universal.zip"},
+		{"no match", githubReleaseAssets, platform{OS: "freebsd", Arch: "amd64", Level: 1}, nil, ""},
+		{"workflow linux", workflowReleaseAssets, platform{OS: "linux", Arch: "amd64", Level: 1}, nil, "myWeatherApp-linux-amd64.tar.gz"},
+		{"workflow darwin", workflowReleaseAssets, platform{OS: "darwin", Arch: "arm64"}, nil, "myWeatherApp-darwin-arm64.tar.gz"},
+		{"workflow windows", workflowReleaseAssets, platform{OS: "windows", Arch: "amd64", Level: 1}, nil, "myWeatherApp-windows-amd64.zip"},
+		{"workflow has no intel mac build", workflowReleaseAssets, platform{OS: "darwin", Arch: "amd64", Level: 1}, nil, ""},
+	}
+
+	for _, tt := range tests {
+		formats := tt.formats
+		if formats == nil {
+			formats = defaultAssetFormats[tt.platform.OS]
+		}
+		asset, ok := selectAsset(releaseAssets(tt.assets), tt.platform, formats)
+		if asset.Name != tt.want || ok != (tt.want != "") {
+			t.Errorf("%s: selectAsset = %q, %v, want %q", tt.name, asset.Name, ok, tt.want)
+		}
+	}
+}
+
+func TestInstallerFormats(t *testing.T) {
+	installers := map[string]bool{
+		"myWeatherApp-amd64-installer.exe":  true,
+		"myWeatherApp-windows-amd64.msi":    true,
+		"myWeatherApp-darwin-universal.dmg": true,
+		"myweatherapp_1.2.0_amd64.deb":      true,
+		"myWeatherApp-windows-amd64.exe":    false,
+		"myWeatherApp-windows-amd64.zip":    false,
+		"myWeatherApp-linux-amd64.AppImage": false,
+	}
+
+	for name, want := range installers {
+		if got := isInstallerFormat(assetFormat(name)); got != want {
+			t.Errorf("%s: installer = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// workflowPlatformPattern matches a build in the release workflow matrix
+var workflowPlatformPattern = regexp.MustCompile(`platform: (\S+)\s+archive: (\S+)`)
+
+func TestWorkflowReleaseAssets(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(".github", "workflows", "release.yml"))
+	if err != nil {
+		t.Skip("release workflow not found:", err)
+	}
+
+	names := []string{"SHA256SUMS", "SHA256SUMS.minisig"}
+	for _, match := range workflowPlatformPattern.FindAllStringSubmatch(string(data), -1) {
+		names = append(names, "myWeatherApp-"+match[1]+"."+match[2])
+	}
+	slices.Sort(names)
+
+	if !slices.Equal(names, workflowReleaseAssets) {
+		t.Errorf("release workflow publishes %q, want %q", names, workflowReleaseAssets)
+	}
+}
@@ -1,10 +1,9 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
 	"runtime"
 	"strings"
 	"time"
@@ -16,105 +15,286 @@ type UpdateInfo struct {
 	ReleaseURL  string `json:"releaseUrl"`
 	DownloadURL string `json:"downloadUrl"`
 	Description string `json:"description"`
-	Available   bool   `json:"available"`
+	// DescriptionHTML is Description rendered from Markdown and sanitised
+	DescriptionHTML string `json:"descriptionHtml"`
+	Available       bool   `json:"available"`
+	// AssetName is the file name of the download, as listed in the
+	// checksums file published at ChecksumsURL
+	AssetName    string `json:"assetName"`
+	ChecksumsURL string `json:"checksumsUrl"`
+	SignatureURL string `json:"signatureUrl"`
+	Channel      string `json:"channel,omitempty"`
+	// Ignored is set when the latest version is on the ignore list
+	Ignored bool `json:"ignored,omitempty"`
+	// Error explains why an available update cannot be installed, e.g. a
+	// missing or invalid signature
+	Error string `json:"error,omitempty"`
 }
 
+// UpdateConfig controls where updates come from and which are offered
+type UpdateConfig struct {
+	// Channel is the least stable channel offered: stable, beta or nightly
+	Channel string `json:"channel"`
+	// Source lists the releases: github, manifest or directory
+	Source string `json:"source"`
+	// Repository is the GitHub repository as owner/name
+	Repository string `json:"repository,omitempty"`
+	// APIURL replaces https://api.github.com, e.g. with a local fake
+	// release server for testing
+	APIURL string `json:"apiUrl,omitempty"`
+	// ManifestURL is the release manifest for the manifest source
+	ManifestURL string `json:"manifestUrl,omitempty"`
+	// Directory holds manifest.json and the assets for the directory source
+	Directory string `json:"directory,omitempty"`
+	// AssetFormats orders the download formats by preference, e.g.
+	// ["appimage", "tar.gz"]; empty uses the platform default
+	AssetFormats []string `json:"assetFormats,omitempty"`
+	// IgnoredVersions are never offered
+	IgnoredVersions []string `json:"ignoredVersions,omitempty"`
+	// AutoCheck checks for updates in the background every
+	// CheckIntervalHours
+	AutoCheck bool `json:"autoCheck"`
+	// CheckIntervalHours is the time between background checks; 0 uses
+	// CheckInterval
+	CheckIntervalHours int `json:"checkIntervalHours,omitempty"`
+}
+
+// defaultGitHubAPIURL is the GitHub REST API used for update checks
+const defaultGitHubAPIURL = "https://api.github.com"
+
+// checksumsAssetNames are the file names a release's SHA-256 checksums
+// file may have
+var checksumsAssetNames = []string{"checksums.txt", "sha256sums", "sha256sums.txt"}
+
 // GitHubRelease represents a GitHub release
 type GitHubRelease struct {
-	TagName string `json:"tag_name"`
-	HTMLURL string `json:"html_url"`
-	Body    string `json:"body"`
-	Assets  []struct {
+	TagName    string `json:"tag_name"`
+	HTMLURL    string `json:"html_url"`
+	Body       string `json:"body"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	Assets     []struct {
 		Name               string `json:"name"`
 		BrowserDownloadURL string `json:"browser_download_url"`
 	} `json:"assets"`
 }
 
 const (
-	CurrentVersion = "v1.0.0"     // Update this with your app version
-	GitHubRepo     = "ehsanpo/myWeatherApp" // Update with your GitHub repo
-	CheckInterval  = 24 * time.Hour
+	GitHubRepo    = "ehsanpo/myWeatherApp" // Update with your GitHub repo
+	CheckInterval = 24 * time.Hour         // between background update checks
 )
 
-// CheckForUpdates checks if a new version is available
+// CheckForUpdates checks if a new version is available on the configured
+// channel and records the result for GetLastUpdateCheck
 func (a *App) CheckForUpdates() (*UpdateInfo, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", GitHubRepo)
+	info, err := a.checkForUpdates()
+	a.recordUpdateCheck(info, err)
+	return info, err
+}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+// checkForUpdates looks up the latest release allowed by the update policy
+func (a *App) checkForUpdates() (*UpdateInfo, error) {
+	config, err := a.LoadConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch release info: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	client := newHTTPClient(10 * time.Second)
+	source, err := a.releaseSource(config, client)
+	if err != nil {
+		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	releases, err := source.Releases()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
-	var release GitHubRelease
-	err = json.Unmarshal(body, &release)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse release: %w", err)
+	release, ok := latestRelease(releases, updateChannel(&config.Updates))
+	if !ok {
+		return &UpdateInfo{Version: currentVersion()}, nil
 	}
 
 	updateInfo := &UpdateInfo{
-		Version:     release.TagName,
-		ReleaseURL:  release.HTMLURL,
-		Description: release.Body,
-		Available:   isNewerVersion(release.TagName, CurrentVersion),
+		Version:         release.Version,
+		ReleaseURL:      release.URL,
+		Description:     release.Notes,
+		DescriptionHTML: renderMarkdown(release.Notes),
+		Channel:         releaseChannel(release),
+		Available:       isNewerVersion(release.Version, currentVersion()),
+	}
+	if updateInfo.Available && isIgnoredVersion(&config.Updates, release.Version) {
+		updateInfo.Available = false
+		updateInfo.Ignored = true
 	}
-
-	// Find download URL for current platform
-	platform := runtime.GOOS
-	arch := runtime.GOARCH
 
 	for _, asset := range release.Assets {
 		name := strings.ToLower(asset.Name)
-		if strings.Contains(name, platform) && strings.Contains(name, arch) {
-			updateInfo.DownloadURL = asset.BrowserDownloadURL
-			break
+		if containsString(checksumsAssetNames, name) {
+			updateInfo.ChecksumsURL = asset.URL
+		}
+		if base, ok := strings.CutSuffix(name, signatureExtension); ok && containsString(checksumsAssetNames, base) {
+			updateInfo.SignatureURL = asset.URL
 		}
 	}
 
+	// Find download URL for current platform
+	if asset, ok := selectAsset(release.Assets, currentPlatform(), assetFormats(&config.Updates, runtime.GOOS)); ok {
+		updateInfo.DownloadURL = asset.URL
+		updateInfo.AssetName = asset.Name
+	}
+
+	if updateInfo.Available {
+		updateInfo.Error = verifyUpdate(client, updateInfo)
+	}
+
 	return updateInfo, nil
 }
 
+// verifyUpdate checks that the release offers a download for this platform
+// whose checksum is signed with the embedded key. It returns a description
+// of the problem, or "" when the update can be installed.
+func verifyUpdate(client *http.Client, info *UpdateInfo) string {
+	if info.DownloadURL == "" {
+		return fmt.Sprintf("no download for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+
+	checksums, err := (&updater{client: client}).verifiedChecksums(info)
+	if err != nil {
+		return err.Error()
+	}
+	if _, ok := checksums[info.AssetName]; !ok {
+		return fmt.Sprintf("signed checksums have no entry for %s", info.AssetName)
+	}
+
+	return ""
+}
+
 // GetCurrentVersion returns the current app version
 func (a *App) GetCurrentVersion() string {
-	return CurrentVersion
+	return currentVersion()
 }
 
-// OpenReleaseURL opens the release page in the browser
+// OpenReleaseURL opens the release page in the browser. Only http and
+// https URLs are opened.
 func (a *App) OpenReleaseURL(url string) error {
-	var cmd string
-	var args []string
-
-	switch runtime.GOOS {
-	case "windows":
-		cmd = "cmd"
-		args = []string{"/c", "start", url}
-	case "darwin":
-		cmd = "open"
-		args = []string{url}
-	default: // linux, etc.
-		cmd = "xdg-open"
-		args = []string{url}
-	}
-
-	// Note: This is a simplified version. In production, use runtime.OpenBrowser() or similar
-	fmt.Printf("Opening URL: %s (command: %s %v) ", url, cmd, args)
+	return openURL(url)
+}
+
+// latestRelease returns the release with the highest version on channel or
+// a more stable one, skipping versions that are not SemVer
+func latestRelease(releases []Release, channel string) (Release, bool) {
+	var best Release
+	var bestVersion Version
+	found := false
+
+	for _, release := range releases {
+		version, err := ParseVersion(release.Version)
+		if err != nil {
+			continue
+		}
+		if !channelIncludes(channel, releaseChannel(release)) {
+			continue
+		}
+		if !found || version.Compare(bestVersion) > 0 {
+			best, bestVersion, found = release, version, true
+		}
+	}
+
+	return best, found
+}
+
+// updateChannel returns the configured channel, stable if none is set
+func updateChannel(config *UpdateConfig) string {
+	if config.Channel == "" {
+		return ReleaseChannelStable
+	}
+	return config.Channel
+}
+
+// isIgnoredVersion reports whether the user chose to skip version
+func isIgnoredVersion(config *UpdateConfig, version string) bool {
+	parsed, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+	for _, ignored := range config.IgnoredVersions {
+		if v, err := ParseVersion(ignored); err == nil && v.Compare(parsed) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// IgnoreVersion stops version from being offered as an update
+func (a *App) IgnoreVersion(version string) error {
+	if _, err := ParseVersion(version); err != nil {
+		return err
+	}
+
+	return a.updateStoredConfig(func(config *AppConfig) (bool, error) {
+		if isIgnoredVersion(&config.Updates, version) {
+			return false, nil
+		}
+		config.Updates.IgnoredVersions = append(config.Updates.IgnoredVersions, version)
+		return true, nil
+	})
+}
+
+// validateUpdateConfig checks the update channel and source settings
+func validateUpdateConfig(config *UpdateConfig) error {
+	if config.Channel != "" && !containsString(releaseChannels, config.Channel) {
+		return fmt.Errorf("unsupported updates.channel: %q", config.Channel)
+	}
+
+	switch config.Source {
+	case ReleaseSourceGitHub, "":
+		if config.Repository != "" && strings.Count(config.Repository, "/") != 1 {
+			return fmt.Errorf("updates.repository must be owner/name: %q", config.Repository)
+		}
+	case ReleaseSourceManifest:
+		parsed, err := url.Parse(config.ManifestURL)
+		if err != nil || !containsString(manifestURLSchemes, parsed.Scheme) || parsed.Host == "" {
+			return fmt.Errorf("updates.manifestUrl must be an https URL: %q", config.ManifestURL)
+		}
+	case ReleaseSourceDirectory:
+		if config.Directory == "" {
+			return fmt.Errorf("updates.directory is required for the directory source")
+		}
+	default:
+		return fmt.Errorf("unsupported updates.source: %q", config.Source)
+	}
+
+	if config.CheckIntervalHours < 0 {
+		return fmt.Errorf("updates.checkIntervalHours must not be negative")
+	}
+
+	for _, format := range config.AssetFormats {
+		if !isAssetFormat(format) {
+			return fmt.Errorf("unsupported updates.assetFormats entry: %q", format)
+		}
+	}
+
+	for _, version := range config.IgnoredVersions {
+		if _, err := ParseVersion(version); err != nil {
+			return fmt.Errorf("updates.ignoredVersions: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// isNewerVersion compares version strings (simple semver comparison)
+// isNewerVersion reports whether latest has higher SemVer precedence than
+// current. Versions that do not parse are never newer.
 func isNewerVersion(latest, current string) bool {
-	latest = strings.TrimPrefix(latest, "v")
-	current = strings.TrimPrefix(current, "v")
+	latestVersion, err := ParseVersion(latest)
+	if err != nil {
+		return false
+	}
+	currentVersion, err := ParseVersion(current)
+	if err != nil {
+		return false
+	}
 
-	return latest > current // Simplified comparison
+	return latestVersion.Compare(currentVersion) > 0
 }
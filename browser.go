@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// validateBrowserURL checks that rawURL is an absolute http or https URL
+// and returns it normalised. Other schemes such as file: or javascript:
+// could run local programs or scripts and are refused.
+func validateBrowserURL(rawURL string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+	default:
+		return "", fmt.Errorf("refusing to open %q: only http and https URLs are allowed", rawURL)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("refusing to open %q: missing host", rawURL)
+	}
+	// user:password@host is a common way to disguise the real host
+	if parsed.User != nil {
+		return "", fmt.Errorf("refusing to open %q: URLs with credentials are not allowed", rawURL)
+	}
+
+	return parsed.String(), nil
+}
+
+// openURL opens an http or https URL in the default browser, through Wails
+// when the app is running and the platform opener otherwise
+func openURL(rawURL string) error {
+	target, err := validateBrowserURL(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if app := application.Get(); app != nil && app.Browser != nil {
+		err := app.Browser.OpenURL(target)
+		if err == nil {
+			return nil
+		}
+		log.Printf("Failed to open URL through Wails, using the platform opener: %v", err)
+	}
+
+	cmd := platformOpenURLCommand(target)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", target, err)
+	}
+	// The opener exits once it has handed the URL to the browser
+	go cmd.Wait()
+	return nil
+}
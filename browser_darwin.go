@@ -0,0 +1,8 @@
+package main
+
+import "os/exec"
+
+// platformOpenURLCommand opens a URL with Launch Services
+func platformOpenURLCommand(url string) *exec.Cmd {
+	return exec.Command("open", url)
+}
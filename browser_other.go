@@ -0,0 +1,10 @@
+//go:build !darwin && !windows
+
+package main
+
+import "os/exec"
+
+// platformOpenURLCommand opens a URL with the desktop's default handler
+func platformOpenURLCommand(url string) *exec.Cmd {
+	return exec.Command("xdg-open", url)
+}
@@ -0,0 +1,9 @@
+package main
+
+import "os/exec"
+
+// platformOpenURLCommand opens a URL with the registered protocol handler.
+// Unlike "cmd /c start", rundll32 does not interpret & and ^ in the URL.
+func platformOpenURLCommand(url string) *exec.Cmd {
+	return exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+}
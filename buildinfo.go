@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Build metadata set at build time, e.g.
+//
+//	go build -ldflags "-X main.version=v1.2.0 -X main.commit=$(git rev-parse HEAD) -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Unset values are filled from the Go build info where possible.
+var (
+	version   string
+	commit    string
+	buildDate string
+)
+
+// devVersion is reported by builds without a version, so every release is
+// newer
+const devVersion = "v0.0.0-dev"
+
+// gitDescribePattern matches "git describe --tags --always --dirty" output:
+// a tag or abbreviated hash, the commits since the tag and the current hash,
+// and "-dirty" for uncommitted changes
+var gitDescribePattern = regexp.MustCompile(`^(.+?)(?:-([0-9]+)-g([0-9a-f]+))?(-dirty)?$`)
+
+// gitHashPattern matches the abbreviated hash git describe prints when no
+// tag is reachable
+var gitHashPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// wailsModulePath identifies the Wails dependency in the build info
+const wailsModulePath = "github.com/wailsapp/wails/v3"
+
+// BuildInfo describes the running build
+type BuildInfo struct {
+	Version      string `json:"version"`
+	Commit       string `json:"commit"`
+	BuildDate    string `json:"buildDate"`
+	Modified     bool   `json:"modified"` // built from a tree with uncommitted changes
+	GoVersion    string `json:"goVersion"`
+	WailsVersion string `json:"wailsVersion"`
+	Platform     string `json:"platform"`
+}
+
+var (
+	buildInfoOnce   sync.Once
+	cachedBuildInfo BuildInfo
+)
+
+// currentBuildInfo returns the build metadata, reading it once
+func currentBuildInfo() BuildInfo {
+	buildInfoOnce.Do(func() {
+		cachedBuildInfo = readBuildInfo()
+	})
+	return cachedBuildInfo
+}
+
+// readBuildInfo combines the injected values with runtime/debug build info
+func readBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if debugInfo, ok := debug.ReadBuildInfo(); ok {
+		// "go install module@version" records the module version
+		if info.Version == "" && debugInfo.Main.Version != "" && debugInfo.Main.Version != "(devel)" {
+			info.Version = debugInfo.Main.Version
+		}
+		for _, setting := range debugInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+		for _, dep := range debugInfo.Deps {
+			if dep.Path == wailsModulePath {
+				info.WailsVersion = dep.Version
+				if dep.Replace != nil {
+					info.WailsVersion = dep.Replace.Version
+				}
+			}
+		}
+	}
+
+	info.Version = normalizeVersion(info.Version)
+	if _, err := ParseVersion(info.Version); err != nil {
+		info.Version = devVersion
+	}
+
+	return info
+}
+
+// normalizeVersion turns git describe output for a build after a tag into
+// a pre-release of the next version, so it is newer than the tag but older
+// than the next release and its pre-releases: v1.2.0-3-gabc123 becomes
+// v1.2.1-0.dev.3+gabc123, and v1.2.0-rc.1-3-gabc123 becomes
+// v1.2.0-rc.1.dev.3+gabc123. Uncommitted
+// changes add "dirty" to the build metadata, and builds without a tag
+// become v0.0.0-dev with the hash as build metadata. Other versions are
+// returned unchanged.
+func normalizeVersion(s string) string {
+	match := gitDescribePattern.FindStringSubmatch(s)
+	if match == nil {
+		return s
+	}
+	tag, commits, hash, dirty := match[1], match[2], match[3], match[4] != ""
+
+	var build []string
+	if hash != "" {
+		build = append(build, "g"+hash)
+	}
+	if dirty {
+		build = append(build, "dirty")
+	}
+
+	if gitHashPattern.MatchString(tag) {
+		return devVersion + "+" + strings.Join(append([]string{"g" + tag}, build...), ".")
+	}
+	if commits == "" && !dirty {
+		return s
+	}
+
+	v, err := ParseVersion(tag)
+	if err != nil {
+		return s
+	}
+	if commits == "" {
+		commits = "0"
+	}
+	if v.IsPrerelease() {
+		v.Prerelease = append(v.Prerelease, "dev", commits)
+	} else {
+		v.Patch++
+		// A leading numeric identifier sorts before alpha, beta and rc
+		v.Prerelease = []string{"0", "dev", commits}
+	}
+	v.Build = build
+
+	return "v" + v.String()
+}
+
+// currentVersion returns the version of the running build
+func currentVersion() string {
+	return currentBuildInfo().Version
+}
+
+// GetBuildInfo returns the version and build details of the app
+func (a *App) GetBuildInfo() BuildInfo {
+	return currentBuildInfo()
+}
+
+// String formats the build info for the About dialog
+func (b BuildInfo) String() string {
+	var lines []string
+	lines = append(lines, "Version: "+b.Version)
+	if b.Commit != "" {
+		commit := b.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		if b.Modified {
+			commit += " (modified)"
+		}
+		lines = append(lines, "Commit: "+commit)
+	}
+	if b.BuildDate != "" {
+		lines = append(lines, "Built: "+b.BuildDate)
+	}
+	lines = append(lines, "Go: "+b.GoVersion)
+	if b.WailsVersion != "" {
+		lines = append(lines, "Wails: "+b.WailsVersion)
+	}
+	lines = append(lines, "Platform: "+b.Platform)
+	return strings.Join(lines, "\n")
+}
+
+// userAgent identifies the app to weather and release servers
+func userAgent() string {
+	info := currentBuildInfo()
+	return fmt.Sprintf("myWeatherApp/%s (%s; +https://github.com/%s)", strings.TrimPrefix(info.Version, "v"), info.Platform, GitHubRepo)
+}
+
+// userAgentTransport sets the User-Agent header on every request
+type userAgentTransport struct {
+	base http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", userAgent())
+	}
+	return t.base.RoundTrip(req)
+}
+
+// newHTTPClient returns a client that sends the app's User-Agent
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &userAgentTransport{base: http.DefaultTransport},
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import "testing"
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"v1.2.0", "v1.2.0"},
+		{"1.2.0", "1.2.0"},
+		{"v1.2.0-rc.1", "v1.2.0-rc.1"},
+		{"v1.2.0-3-gabc1234", "v1.2.1-0.dev.3+gabc1234"},
+		{"v1.2.0-3-gabc1234-dirty", "v1.2.1-0.dev.3+gabc1234.dirty"},
+		{"v1.2.0-dirty", "v1.2.1-0.dev.0+dirty"},
+		{"v1.2.0-rc.1-12-g0123abc", "v1.2.0-rc.1.dev.12+g0123abc"},
+		{"abc1234", "v0.0.0-dev+gabc1234"},
+		{"abc1234-dirty", "v0.0.0-dev+gabc1234.dirty"},
+		{"v0.0.0-dev", "v0.0.0-dev"},
+		{"", ""},
+		{"not-a-version-5-gabc1234", "not-a-version-5-gabc1234"},
+	}
+
+	for _, tt := range tests {
+		got := normalizeVersion(tt.input)
+		if got != tt.want {
+			t.Errorf("normalizeVersion(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+		if tt.want != "" && tt.want != tt.input {
+			if _, err := ParseVersion(got); err != nil {
+				t.Errorf("normalizeVersion(%q) = %q is not valid SemVer: %v", tt.input, got, err)
+			}
+		}
+	}
+}
+
+func TestDevBuildUpdateOffers(t *testing.T) {
+	tests := []struct {
+		build   string
+		release string
+		newer   bool
+	}{
+		// A build after a tag is not offered that tag again
+		{"v1.2.0-3-gabc1234", "v1.2.0", false},
+		{"v1.2.0-dirty", "v1.2.0", false},
+		{"v1.2.0-3-gabc1234", "v1.2.1", true},
+		{"v1.2.0-3-gabc1234", "v1.2.1-beta.1", true},
+		{"v1.2.0-rc.1-3-gabc1234", "v1.2.0-rc.1", false},
+		{"v1.2.0-rc.1-3-gabc1234", "v1.2.0-rc.2", true},
+		{"v1.2.0-rc.1-3-gabc1234", "v1.2.0", true},
+		// Builds without a tag are older than every release
+		{"abc1234", "v0.1.0", true},
+	}
+
+	for _, tt := range tests {
+		current := normalizeVersion(tt.build)
+		if got := isNewerVersion(tt.release, current); got != tt.newer {
+			t.Errorf("isNewerVersion(%q, %q) = %v, want %v", tt.release, current, got, tt.newer)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// cliUsage describes the command-line subcommands
+const cliUsage = `Usage: myWeatherApp [command]
+
+Commands:
+  export-config <file>                 Export the configuration to a file
+  import-config [-replace] <file>      Import a configuration file (merges by default)
+  profiles                             List saved profiles
+  save-profile <name>                  Save the current configuration as a profile
+  use-profile <name>                   Switch to a saved profile
+  delete-profile <name>                Delete a saved profile
+
+Without a command the tray application is started.
+`
+
+// errUnknownCommand is returned for arguments that name no command
+var errUnknownCommand = errors.New("unknown command")
+
+// runCommand runs a command-line subcommand that works on the configuration
+// without starting the GUI. It reports whether any arguments were given;
+// arguments that name no command return errUnknownCommand.
+func runCommand(app *App, args []string, out io.Writer) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	command, rest := args[0], args[1:]
+	switch command {
+	case "export-config":
+		if len(rest) != 1 {
+			return true, fmt.Errorf("usage: export-config <file>")
+		}
+		if err := app.ExportConfig(rest[0]); err != nil {
+			return true, err
+		}
+		fmt.Fprintf(out, "Configuration exported to %s\n", rest[0])
+
+	case "import-config":
+		flags := flag.NewFlagSet("import-config", flag.ContinueOnError)
+		flags.SetOutput(out)
+		replace := flags.Bool("replace", false, "replace the configuration instead of merging")
+		if err := flags.Parse(rest); err != nil {
+			return true, err
+		}
+		if flags.NArg() != 1 {
+			return true, fmt.Errorf("usage: import-config [-replace] <file>")
+		}
+
+		mode := ImportModeMerge
+		if *replace {
+			mode = ImportModeReplace
+		}
+		if _, err := app.ImportConfig(flags.Arg(0), mode); err != nil {
+			return true, err
+		}
+		fmt.Fprintf(out, "Configuration imported from %s (%s)\n", flags.Arg(0), mode)
+
+	case "profiles":
+		profiles, err := app.ListProfiles()
+		if err != nil {
+			return true, err
+		}
+		active, err := app.GetActiveProfile()
+		if err != nil {
+			return true, err
+		}
+		for _, name := range profiles {
+			marker := " "
+			if name == active {
+				marker = "*"
+			}
+			fmt.Fprintf(out, "%s %s\n", marker, name)
+		}
+
+	case "save-profile", "use-profile", "delete-profile":
+		if len(rest) != 1 {
+			return true, fmt.Errorf("usage: %s <name>", command)
+		}
+
+		var err error
+		switch command {
+		case "save-profile":
+			err = app.SaveProfile(rest[0])
+		case "use-profile":
+			err = app.SwitchProfile(rest[0])
+		case "delete-profile":
+			err = app.DeleteProfile(rest[0])
+		}
+		if err != nil {
+			return true, err
+		}
+
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(out, cliUsage)
+
+	default:
+		return true, fmt.Errorf("%w: %s", errUnknownCommand, command)
+	}
+
+	return true, nil
+}
+
+// exitOnCommand runs a subcommand if one was given and exits the process
+// once it has finished
+func exitOnCommand(app *App, args []string) {
+	handled, err := runCommand(app, args, os.Stdout)
+	if !handled {
+		return
+	}
+
+	if errors.Is(err, errUnknownCommand) {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s", err, cliUsage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunCommand(t *testing.T) {
+	setTestHome(t)
+
+	tests := []struct {
+		args    []string
+		handled bool
+		err     error
+		output  string
+	}{
+		{args: nil, handled: false},
+		{args: []string{"help"}, handled: true, output: "Usage: myWeatherApp"},
+		{args: []string{"exprot"}, handled: true, err: errUnknownCommand},
+	}
+
+	for _, tt := range tests {
+		var out strings.Builder
+		handled, err := runCommand(&App{}, tt.args, &out)
+		if handled != tt.handled {
+			t.Errorf("runCommand(%q) handled = %v, want %v", tt.args, handled, tt.handled)
+		}
+		if tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("runCommand(%q) error = %v, want %v", tt.args, err, tt.err)
+		}
+		if tt.err == nil && err != nil {
+			t.Errorf("runCommand(%q) error = %v", tt.args, err)
+		}
+		if !strings.Contains(out.String(), tt.output) {
+			t.Errorf("runCommand(%q) output = %q, want %q", tt.args, out.String(), tt.output)
+		}
+	}
+}
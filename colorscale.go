@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// ColorScale maps temperatures to colours by interpolating between stops
+type ColorScale struct {
+	// Stops are in ascending temperature order; temperatures outside the
+	// first and last stop take the colour of that stop
+	Stops []ColorStop `json:"stops,omitempty"`
+	// ConditionBlend mixes in the condition colour, from 0 (temperature
+	// only) to 1 (condition only)
+	ConditionBlend float64 `json:"conditionBlend,omitempty"`
+}
+
+// ColorStop is a "#rrggbb" colour at a temperature in °C, whatever the
+// configured temperatureUnit
+type ColorStop struct {
+	Temperature float64 `json:"temperature"`
+	Color       string  `json:"color"`
+}
+
+// defaultColorStops runs from deep blue for freezing through red for heat
+var defaultColorStops = []ColorStop{
+	{Temperature: -20, Color: "#1a237e"},
+	{Temperature: -5, Color: "#1e88e5"},
+	{Temperature: 5, Color: "#4dd0e1"},
+	{Temperature: 15, Color: "#66bb6a"},
+	{Temperature: 22, Color: "#fdd835"},
+	{Temperature: 30, Color: "#fb8c00"},
+	{Temperature: 38, Color: "#c62828"},
+}
+
+// validateColorScale checks that the stops are ordered and parse
+func validateColorScale(scale *ColorScale) error {
+	if scale.ConditionBlend < 0 || scale.ConditionBlend > 1 {
+		return fmt.Errorf("trayIcon.colorScale conditionBlend must be between 0 and 1")
+	}
+
+	if len(scale.Stops) == 1 {
+		return fmt.Errorf("trayIcon.colorScale needs at least two stops")
+	}
+	for i, stop := range scale.Stops {
+		if i > 0 && stop.Temperature <= scale.Stops[i-1].Temperature {
+			return fmt.Errorf("trayIcon.colorScale stops must be in ascending order")
+		}
+		if _, err := parseHexColor(stop.Color); err != nil {
+			return fmt.Errorf("invalid colour for stop %v in trayIcon.colorScale: %w", stop.Temperature, err)
+		}
+	}
+
+	return nil
+}
+
+// temperatureScaleColor returns the scale colour for the weather, blended
+// with the condition colour when configured
+func temperatureScaleColor(weather *WeatherData, scale *ColorScale) color.RGBA {
+	stops := defaultColorStops
+	blend := 0.0
+	if scale != nil {
+		if len(scale.Stops) > 0 {
+			stops = scale.Stops
+		}
+		blend = scale.ConditionBlend
+	}
+
+	c := interpolateColorStops(stops, weather.Temperature)
+	if blend > 0 {
+		c = mixColors(c, conditionColor(weather), blend)
+	}
+	return c
+}
+
+// interpolateColorStops linearly interpolates the colour at temperature,
+// clamping to the first and last stop. Stops must already be validated.
+func interpolateColorStops(stops []ColorStop, temperature float64) color.RGBA {
+	if len(stops) == 0 {
+		return color.RGBA{}
+	}
+
+	colors := make([]color.RGBA, len(stops))
+	for i, stop := range stops {
+		colors[i], _ = parseHexColor(stop.Color)
+	}
+
+	if temperature <= stops[0].Temperature {
+		return colors[0]
+	}
+	for i := 1; i < len(stops); i++ {
+		if temperature <= stops[i].Temperature {
+			low, high := stops[i-1].Temperature, stops[i].Temperature
+			return mixColors(colors[i-1], colors[i], (temperature-low)/(high-low))
+		}
+	}
+	return colors[len(colors)-1]
+}
+
+// mixColors returns a + (b - a) * t per channel, rounded to the nearest
+// integer so the result is the same on every platform
+func mixColors(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t))
+	}
+	return color.RGBA{
+		R: mix(a.R, b.R),
+		G: mix(a.G, b.G),
+		B: mix(a.B, b.B),
+		A: mix(a.A, b.A),
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestInterpolateColorStops(t *testing.T) {
+	stops := []ColorStop{
+		{Temperature: 0, Color: "#000000"},
+		{Temperature: 10, Color: "#ffffff"},
+		{Temperature: 30, Color: "#ff0000"},
+	}
+
+	tests := []struct {
+		temperature float64
+		want        color.RGBA
+	}{
+		{-40, color.RGBA{0, 0, 0, 255}},      // below the first stop
+		{0, color.RGBA{0, 0, 0, 255}},        // first stop
+		{5, color.RGBA{128, 128, 128, 255}},  // midpoint, rounded half up
+		{2.5, color.RGBA{64, 64, 64, 255}},   // quarter
+		{10, color.RGBA{255, 255, 255, 255}}, // middle stop
+		{20, color.RGBA{255, 128, 128, 255}}, // midpoint of the second segment
+		{30, color.RGBA{255, 0, 0, 255}},     // last stop
+		{45, color.RGBA{255, 0, 0, 255}},     // above the last stop
+	}
+
+	for _, tt := range tests {
+		if got := interpolateColorStops(stops, tt.temperature); got != tt.want {
+			t.Errorf("interpolateColorStops(%v) = %v, want %v", tt.temperature, got, tt.want)
+		}
+	}
+}
+
+func TestTemperatureScaleColor(t *testing.T) {
+	weather := &WeatherData{Temperature: -30, WeatherCode: 0, IsDay: true}
+
+	first, _ := parseHexColor(defaultColorStops[0].Color)
+	if got := temperatureScaleColor(weather, nil); got != first {
+		t.Errorf("default scale at -30 = %v, want %v", got, first)
+	}
+
+	condition := conditionColor(weather)
+	if got := temperatureScaleColor(weather, &ColorScale{ConditionBlend: 1}); got != condition {
+		t.Errorf("full condition blend = %v, want %v", got, condition)
+	}
+}
+
+func TestValidateColorScale(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale ColorScale
+		valid bool
+	}{
+		{"defaults", ColorScale{}, true},
+		{"ascending", ColorScale{Stops: []ColorStop{{0, "#000000"}, {10, "#ffffff"}}}, true},
+		{"single stop", ColorScale{Stops: []ColorStop{{0, "#000000"}}}, false},
+		{"descending", ColorScale{Stops: []ColorStop{{10, "#000000"}, {0, "#ffffff"}}}, false},
+		{"bad colour", ColorScale{Stops: []ColorStop{{0, "black"}, {10, "#ffffff"}}}, false},
+		{"blend out of range", ColorScale{ConditionBlend: 1.5}, false},
+	}
+
+	for _, tt := range tests {
+		if err := validateColorScale(&tt.scale); (err == nil) != tt.valid {
+			t.Errorf("%s: validateColorScale = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestPaletteBandsUseCelsius(t *testing.T) {
+	config := &TrayIconConfig{Palette: &TrayIconPalette{
+		TemperatureBands: []TemperatureBand{{Min: 0, Color: "#ff0000"}, {Min: 20, Color: "#0000ff"}},
+	}}
+
+	// 10 °C is 50 °F, which would fall in the second band if it were
+	// compared in °F
+	got := backgroundColor(&WeatherData{Temperature: 10}, config)
+	if want, _ := parseHexColor("#ff0000"); got != want {
+		t.Errorf("backgroundColor(10 °C) = %v, want %v", got, want)
+	}
+}
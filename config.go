@@ -13,6 +13,23 @@ type AppConfig struct {
 	WindowWidth    int                    `json:"windowWidth"`
 	WindowHeight   int                    `json:"windowHeight"`
 	CustomSettings map[string]interface{} `json:"customSettings"`
+	ActiveProfile  string                 `json:"activeProfile,omitempty"`
+	SecretsBackend string                 `json:"secretsBackend"`
+	GitHubToken    SecretRef              `json:"githubToken,omitempty"`
+	TrayIcon       TrayIconConfig         `json:"trayIcon"`
+	// TrayLabel is the tray label template, e.g. "{location}: {temp}{unit}"
+	TrayLabel string `json:"trayLabel"`
+	// TrayTooltip is the tooltip template; empty uses the default for the
+	// configured language
+	TrayTooltip string       `json:"trayTooltip,omitempty"`
+	Updates     UpdateConfig `json:"updates"`
+	// Startup configures launching at login
+	Startup StartupConfig `json:"startup"`
+	// Alerts sets the temperatures that raise an alert
+	Alerts AlertConfig `json:"alerts"`
+
+	// WindowPositions remembers the window position per screen ID
+	WindowPositions map[string]WindowPosition `json:"windowPositions,omitempty"`
 }
 
 // GetConfigPath returns the path to the config file
@@ -31,16 +48,35 @@ func (a *App) GetConfigPath() (string, error) {
 	return filepath.Join(configDir, "config.json"), nil
 }
 
-// LoadConfig loads the application configuration
+// LoadConfig loads the effective application configuration: the defaults,
+// overlaid by the config file, then environment variables, then flags
 func (a *App) LoadConfig() (*AppConfig, error) {
+	config, err := a.loadStoredConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.applyOverrides(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// loadStoredConfig loads the defaults overlaid by the config file, without
+// environment or command-line overrides. Use it when the result is saved
+// back so overrides never leak into the file.
+func (a *App) loadStoredConfig() (*AppConfig, error) {
 	configPath, err := a.GetConfigPath()
 	if err != nil {
 		return nil, err
 	}
 
+	config := a.GetDefaultConfig()
+
 	// Return default config if file doesn't exist
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return a.GetDefaultConfig(), nil
+		return config, nil
 	}
 
 	data, err := os.ReadFile(configPath)
@@ -48,17 +84,90 @@ func (a *App) LoadConfig() (*AppConfig, error) {
 		return nil, err
 	}
 
-	var config AppConfig
-	err = json.Unmarshal(data, &config)
+	err = json.Unmarshal(data, config)
 	if err != nil {
 		return nil, err
 	}
 
-	return &config, nil
+	return config, nil
 }
 
-// SaveConfig saves the application configuration
+// SaveConfig validates and saves the application configuration and applies
+// it to the running app
 func (a *App) SaveConfig(config *AppConfig) error {
+	if err := validateConfig(config); err != nil {
+		return err
+	}
+
+	a.configMu.Lock()
+	err := a.saveConfigLocked(config)
+	a.configMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	a.applyEffectiveConfig()
+	return nil
+}
+
+// saveConfigLocked writes a validated configuration. Callers hold configMu.
+func (a *App) saveConfigLocked(config *AppConfig) error {
+	previousFont := ""
+	if stored, err := a.loadStoredConfig(); err == nil {
+		previousFont = stored.TrayIcon.FontFile
+		// Window positions are app state the caller may not have loaded
+		if config.WindowPositions == nil {
+			config.WindowPositions = stored.WindowPositions
+		}
+	}
+
+	if err := validateTrayIconFont(&config.TrayIcon, previousFont); err != nil {
+		return err
+	}
+
+	return a.writeConfig(config)
+}
+
+// applyEffectiveConfig applies the effective config to the running app, so
+// overrides keep precedence over saved values
+func (a *App) applyEffectiveConfig() {
+	if effective, err := a.LoadConfig(); err == nil {
+		a.applyWindowConfig(effective)
+		if a.configAppliedFunc != nil {
+			a.configAppliedFunc(effective)
+		}
+	}
+}
+
+// SetConfigAppliedFunc sets the function called with the effective config
+// whenever a saved configuration is applied
+func (a *App) SetConfigAppliedFunc(appliedFunc func(config *AppConfig)) {
+	a.configAppliedFunc = appliedFunc
+}
+
+// updateStoredConfig loads the stored configuration, lets update change it
+// and writes it back. configMu is held throughout so concurrent changes are
+// not lost. update returns false to leave the file as it is.
+func (a *App) updateStoredConfig(update func(config *AppConfig) (bool, error)) error {
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
+
+	config, err := a.loadStoredConfig()
+	if err != nil {
+		return err
+	}
+
+	write, err := update(config)
+	if err != nil || !write {
+		return err
+	}
+
+	return a.writeConfig(config)
+}
+
+// writeConfig writes the configuration file without applying it. Callers
+// hold configMu.
+func (a *App) writeConfig(config *AppConfig) error {
 	configPath, err := a.GetConfigPath()
 	if err != nil {
 		return err
@@ -75,10 +184,29 @@ func (a *App) SaveConfig(config *AppConfig) error {
 // GetDefaultConfig returns the default configuration
 func (a *App) GetDefaultConfig() *AppConfig {
 	return &AppConfig{
-		Theme:        "light",
-		Language:     "en",
-		WindowWidth:  400,
-		WindowHeight: 600,
+		Theme:          "light",
+		Language:       "en",
+		WindowWidth:    400,
+		WindowHeight:   450,
+		SecretsBackend: SecretsBackendAuto,
+		TrayLabel:      defaultTrayLabel,
+		TrayIcon: TrayIconConfig{
+			Style:        TrayIconStyleNumber,
+			AnimationFPS: defaultAnimationFPS,
+		},
+		Updates: UpdateConfig{
+			Channel:   ReleaseChannelStable,
+			Source:    ReleaseSourceGitHub,
+			AutoCheck: true,
+		},
+		Alerts: AlertConfig{
+			ExtremeCold: defaultExtremeCold,
+			ExtremeHeat: defaultExtremeHeat,
+		},
+		Startup: StartupConfig{
+			Mechanism:   StartupMechanismXDG,
+			RepairStale: true,
+		},
 		CustomSettings: map[string]interface{}{
 			"weatherLocation": "New York",
 			"updateInterval":  300, // 5 minutes in seconds
@@ -103,15 +231,17 @@ func (a *App) GetSetting(key string) (interface{}, error) {
 
 // SetSetting sets a specific setting value
 func (a *App) SetSetting(key string, value interface{}) error {
-	config, err := a.LoadConfig()
+	err := a.updateStoredConfig(func(config *AppConfig) (bool, error) {
+		if config.CustomSettings == nil {
+			config.CustomSettings = make(map[string]interface{})
+		}
+		config.CustomSettings[key] = value
+		return true, validateConfig(config)
+	})
 	if err != nil {
 		return err
 	}
 
-	if config.CustomSettings == nil {
-		config.CustomSettings = make(map[string]interface{})
-	}
-
-	config.CustomSettings[key] = value
-	return a.SaveConfig(config)
+	a.applyEffectiveConfig()
+	return nil
 }
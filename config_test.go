@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSaveConfigValidates(t *testing.T) {
+	setTestHome(t)
+	app := &App{}
+
+	for _, size := range [][2]int{{10, 450}, {400, 10000}} {
+		config := app.GetDefaultConfig()
+		config.WindowWidth, config.WindowHeight = size[0], size[1]
+		if err := app.SaveConfig(config); err == nil {
+			t.Errorf("SaveConfig accepted window size %dx%d", size[0], size[1])
+		}
+	}
+}
+
+func TestSaveConfigKeepsWindowPositions(t *testing.T) {
+	setTestHome(t)
+	app := &App{}
+
+	err := app.updateStoredConfig(func(config *AppConfig) (bool, error) {
+		config.WindowPositions = map[string]WindowPosition{"screen-1": {X: 10, Y: 20}}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := app.GetDefaultConfig()
+	config.Theme = "dark"
+	if err := app.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := app.loadStoredConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Theme != "dark" || stored.WindowPositions["screen-1"] != (WindowPosition{X: 10, Y: 20}) {
+		t.Errorf("stored config = theme %q, positions %v", stored.Theme, stored.WindowPositions)
+	}
+}
+
+func TestUpdateStoredConfigConcurrent(t *testing.T) {
+	setTestHome(t)
+	app := &App{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if err := app.IgnoreVersion(fmt.Sprintf("v1.0.%d", i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			if err := app.SetSetting(fmt.Sprintf("key%d", i), i); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	config, err := app.loadStoredConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Updates.IgnoredVersions) != 20 {
+		t.Errorf("ignored versions = %d, want 20", len(config.Updates.IgnoredVersions))
+	}
+	for i := 0; i < 20; i++ {
+		if _, ok := config.CustomSettings[fmt.Sprintf("key%d", i)]; !ok {
+			t.Errorf("setting key%d was lost", i)
+		}
+	}
+}
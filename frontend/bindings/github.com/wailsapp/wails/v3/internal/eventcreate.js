@@ -12,12 +12,21 @@ import * as main$0 from "../../../../../weatherApp/models.js";
 
 function configure() {
     Object.freeze(Object.assign($Create.Events, {
-        "trayIconUpdate": $$createType1,
+        "appearanceChanged": $$createType1,
+        "trayIconUpdate": $$createType3,
+        "updateAvailable": $$createType5,
+        "updateProgress": $$createType7,
     }));
 }
 
 // Private type creation functions
-const $$createType0 = main$0.WeatherData.createFrom;
+const $$createType0 = main$0.Appearance.createFrom;
 const $$createType1 = $Create.Nullable($$createType0);
+const $$createType2 = main$0.WeatherData.createFrom;
+const $$createType3 = $Create.Nullable($$createType2);
+const $$createType4 = main$0.UpdateInfo.createFrom;
+const $$createType5 = $Create.Nullable($$createType4);
+const $$createType6 = main$0.UpdateProgress.createFrom;
+const $$createType7 = $Create.Nullable($$createType6);
 
 configure();
@@ -12,7 +12,10 @@ import type * as main$0 from "../../../../../weatherApp/models.js";
 declare module "@wailsio/runtime" {
     namespace Events {
         interface CustomEvents {
+            "appearanceChanged": main$0.Appearance | null;
             "trayIconUpdate": main$0.WeatherData | null;
+            "updateAvailable": main$0.UpdateInfo | null;
+            "updateProgress": main$0.UpdateProgress | null;
         }
     }
 }
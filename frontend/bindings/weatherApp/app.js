@@ -16,7 +16,17 @@ import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Cr
 import * as $models from "./models.js";
 
 /**
- * CheckForUpdates checks if a new version is available
+ * AcknowledgeAlert marks the current alert as seen, which stops the tray
+ * icon from blinking
+ * @returns {$CancellablePromise<void>}
+ */
+export function AcknowledgeAlert() {
+    return $Call.ByID(1971516527);
+}
+
+/**
+ * CheckForUpdates checks if a new version is available on the configured
+ * channel and records the result for GetLastUpdateCheck
  * @returns {$CancellablePromise<$models.UpdateInfo | null>}
  */
 export function CheckForUpdates() {
@@ -25,6 +35,25 @@ export function CheckForUpdates() {
     }));
 }
 
+/**
+ * DeleteProfile removes a saved profile. The current configuration is kept
+ * when the active profile is deleted.
+ * @param {string} name
+ * @returns {$CancellablePromise<void>}
+ */
+export function DeleteProfile(name) {
+    return $Call.ByID(2457157279, name);
+}
+
+/**
+ * DeleteSecret removes a stored secret
+ * @param {string} name
+ * @returns {$CancellablePromise<void>}
+ */
+export function DeleteSecret(name) {
+    return $Call.ByID(2628181874, name);
+}
+
 /**
  * DisableStartup disables the app from launching on system startup
  * @returns {$CancellablePromise<void>}
@@ -41,6 +70,54 @@ export function EnableStartup() {
     return $Call.ByID(561757899);
 }
 
+/**
+ * ExportConfig writes the current configuration to a portable file
+ * @param {string} path
+ * @returns {$CancellablePromise<void>}
+ */
+export function ExportConfig(path) {
+    return $Call.ByID(2222068969, path);
+}
+
+/**
+ * GetActiveAlert returns the current alert, or nil when there is none
+ * @returns {$CancellablePromise<$models.WeatherAlert | null>}
+ */
+export function GetActiveAlert() {
+    return $Call.ByID(1499988437).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType3($result);
+    }));
+}
+
+/**
+ * GetActiveProfile returns the name of the active profile, or an empty
+ * string if no profile is active
+ * @returns {$CancellablePromise<string>}
+ */
+export function GetActiveProfile() {
+    return $Call.ByID(1789669858);
+}
+
+/**
+ * GetAppearance returns the resolved theme and language for the frontend
+ * @returns {$CancellablePromise<$models.Appearance | null>}
+ */
+export function GetAppearance() {
+    return $Call.ByID(3469612837).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType5($result);
+    }));
+}
+
+/**
+ * GetBuildInfo returns the version and build details of the app
+ * @returns {$CancellablePromise<$models.BuildInfo>}
+ */
+export function GetBuildInfo() {
+    return $Call.ByID(3168473285).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType6($result);
+    }));
+}
+
 /**
  * GetConfigPath returns the path to the config file
  * @returns {$CancellablePromise<string>}
@@ -63,10 +140,63 @@ export function GetCurrentVersion() {
  */
 export function GetDefaultConfig() {
     return $Call.ByID(3182091318).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType3($result);
+        return $$createType8($result);
+    }));
+}
+
+/**
+ * GetEffectiveSetting returns the effective value of one setting and the
+ * source it came from
+ * @param {string} key
+ * @returns {$CancellablePromise<$models.EffectiveSetting | null>}
+ */
+export function GetEffectiveSetting(key) {
+    return $Call.ByID(890116196, key).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType10($result);
+    }));
+}
+
+/**
+ * GetEffectiveSettings returns every setting with its effective value and
+ * the source it came from
+ * @returns {$CancellablePromise<$models.EffectiveSetting[]>}
+ */
+export function GetEffectiveSettings() {
+    return $Call.ByID(2620386357).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType11($result);
+    }));
+}
+
+/**
+ * GetLastUpdateCheck returns the result of the last update check, or nil
+ * if updates were never checked
+ * @returns {$CancellablePromise<$models.UpdateCheckResult | null>}
+ */
+export function GetLastUpdateCheck() {
+    return $Call.ByID(2461848654).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType13($result);
+    }));
+}
+
+/**
+ * GetMessages returns the message catalogue for the configured language so
+ * the frontend can translate its labels
+ * @returns {$CancellablePromise<{ [_: string]: string }>}
+ */
+export function GetMessages() {
+    return $Call.ByID(3832618599).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType14($result);
     }));
 }
 
+/**
+ * GetSecretsBackend returns the name of the secret storage backend in use
+ * @returns {$CancellablePromise<string>}
+ */
+export function GetSecretsBackend() {
+    return $Call.ByID(2708740612);
+}
+
 /**
  * GetSetting gets a specific setting value
  * @param {string} key
@@ -76,6 +206,47 @@ export function GetSetting(key) {
     return $Call.ByID(48053349, key);
 }
 
+/**
+ * GetStartupVerification returns the result of the startup check made at
+ * launch or by RepairStartup, or nil before the first check completes
+ * @returns {$CancellablePromise<$models.StartupVerification | null>}
+ */
+export function GetStartupVerification() {
+    return $Call.ByID(461685243).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType16($result);
+    }));
+}
+
+/**
+ * GetSupportedLanguages returns the languages with a message catalogue
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function GetSupportedLanguages() {
+    return $Call.ByID(3051367130).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType17($result);
+    }));
+}
+
+/**
+ * GetTrayIconStyles returns the tray icon styles the user can choose from
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function GetTrayIconStyles() {
+    return $Call.ByID(3950779244).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType17($result);
+    }));
+}
+
+/**
+ * HasSecret reports whether a secret with the given name is stored. The
+ * value itself is never returned to the frontend.
+ * @param {string} name
+ * @returns {$CancellablePromise<boolean>}
+ */
+export function HasSecret(name) {
+    return $Call.ByID(717285451, name);
+}
+
 /**
  * HideWindow hides the main window
  * @returns {$CancellablePromise<void>}
@@ -85,20 +256,67 @@ export function HideWindow() {
 }
 
 /**
- * IsStartupEnabled checks if startup is enabled
- * @returns {$CancellablePromise<boolean>}
+ * IgnoreVersion stops version from being offered as an update
+ * @param {string} version
+ * @returns {$CancellablePromise<void>}
+ */
+export function IgnoreVersion(version) {
+    return $Call.ByID(3740422549, version);
+}
+
+/**
+ * ImportConfig reads a configuration exported by ExportConfig and applies it
+ * using merge or replace semantics. The resulting configuration is returned.
+ * @param {string} path
+ * @param {string} mode
+ * @returns {$CancellablePromise<$models.AppConfig | null>}
+ */
+export function ImportConfig(path, mode) {
+    return $Call.ByID(3706958138, path, mode).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType8($result);
+    }));
+}
+
+/**
+ * InstallUpdate downloads the update found by CheckForUpdates, verifies its
+ * signed SHA-256 checksum, replaces the running binary and restarts the app. Progress
+ * is reported through the "updateProgress" event.
+ * @returns {$CancellablePromise<void>}
+ */
+export function InstallUpdate() {
+    return $Call.ByID(2443992793);
+}
+
+/**
+ * IsStartupEnabled reports whether startup is enabled, with which mechanism,
+ * whether the entry starts the running executable and whether its
+ * executable still exists
+ * @returns {$CancellablePromise<$models.StartupStatus | null>}
  */
 export function IsStartupEnabled() {
-    return $Call.ByID(3872694095);
+    return $Call.ByID(3872694095).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType19($result);
+    }));
+}
+
+/**
+ * ListProfiles returns the names of all saved profiles
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function ListProfiles() {
+    return $Call.ByID(1957526603).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType17($result);
+    }));
 }
 
 /**
- * LoadConfig loads the application configuration
+ * LoadConfig loads the effective application configuration: the defaults,
+ * overlaid by the config file, then environment variables, then flags
  * @returns {$CancellablePromise<$models.AppConfig | null>}
  */
 export function LoadConfig() {
     return $Call.ByID(2822453907).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType3($result);
+        return $$createType8($result);
     }));
 }
 
@@ -111,7 +329,8 @@ export function MinimizeWindow() {
 }
 
 /**
- * OpenReleaseURL opens the release page in the browser
+ * OpenReleaseURL opens the release page in the browser. Only http and
+ * https URLs are opened.
  * @param {string} url
  * @returns {$CancellablePromise<void>}
  */
@@ -120,7 +339,8 @@ export function OpenReleaseURL(url) {
 }
 
 /**
- * PositionWindowNearTray positions the window near the system tray
+ * PositionWindowNearTray positions the window near the system tray, or at
+ * the position it was last moved to on the current screen
  * @returns {$CancellablePromise<void>}
  */
 export function PositionWindowNearTray() {
@@ -128,7 +348,29 @@ export function PositionWindowNearTray() {
 }
 
 /**
- * SaveConfig saves the application configuration
+ * RenderReleaseNotes converts release notes Markdown to sanitised HTML for
+ * a frontend to display
+ * @param {string} markdown
+ * @returns {$CancellablePromise<string>}
+ */
+export function RenderReleaseNotes(markdown) {
+    return $Call.ByID(1517317483, markdown);
+}
+
+/**
+ * RepairStartup re-registers a stale startup entry for the running
+ * executable, keeping its arguments
+ * @returns {$CancellablePromise<$models.StartupVerification | null>}
+ */
+export function RepairStartup() {
+    return $Call.ByID(100349709).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType16($result);
+    }));
+}
+
+/**
+ * SaveConfig validates and saves the application configuration and applies
+ * it to the running app
  * @param {$models.AppConfig | null} config
  * @returns {$CancellablePromise<void>}
  */
@@ -136,6 +378,65 @@ export function SaveConfig(config) {
     return $Call.ByID(3442292638, config);
 }
 
+/**
+ * SaveProfile stores the current configuration as a named profile and makes
+ * it the active profile
+ * @param {string} name
+ * @returns {$CancellablePromise<void>}
+ */
+export function SaveProfile(name) {
+    return $Call.ByID(406053493, name);
+}
+
+/**
+ * SetAlertChangedFunc sets the function called when an alert is raised,
+ * cleared or acknowledged
+ * @param {any} changedFunc
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetAlertChangedFunc(changedFunc) {
+    return $Call.ByID(3821532607, changedFunc);
+}
+
+/**
+ * SetConfigAppliedFunc sets the function called with the effective config
+ * whenever a saved configuration is applied
+ * @param {any} appliedFunc
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetConfigAppliedFunc(appliedFunc) {
+    return $Call.ByID(2598907048, appliedFunc);
+}
+
+/**
+ * SetLaunchOptions stores the command-line options used to resolve settings
+ * @param {$models.LaunchOptions | null} options
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetLaunchOptions(options) {
+    return $Call.ByID(3503623434, options);
+}
+
+/**
+ * SetProfilesChangedFunc sets the function called after profiles are
+ * created, switched or deleted
+ * @param {any} changedFunc
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetProfilesChangedFunc(changedFunc) {
+    return $Call.ByID(322946977, changedFunc);
+}
+
+/**
+ * SetSecret stores a secret under the given name
+ * @param {string} name
+ * @param {string} value
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetSecret(name, value) {
+    return $Call.ByID(1752471195, name, value);
+}
+
 /**
  * SetSetting sets a specific setting value
  * @param {string} key
@@ -146,8 +447,47 @@ export function SetSetting(key, value) {
     return $Call.ByID(4214292049, key, value);
 }
 
+/**
+ * StartUpdateChecks checks for updates in the background whenever the last
+ * check is older than the configured interval, including across restarts,
+ * and calls notify when an update is available. The configuration is read
+ * on every wake-up, so interval changes apply without a restart.
+ * @param {any} notify
+ * @returns {$CancellablePromise<void>}
+ */
+export function StartUpdateChecks(notify) {
+    return $Call.ByID(3718991151, notify);
+}
+
+/**
+ * SwitchProfile makes a saved profile the current configuration. The current
+ * configuration is stored back to the active profile first so no changes are
+ * lost. Switching to the active profile keeps the current configuration.
+ * @param {string} name
+ * @returns {$CancellablePromise<void>}
+ */
+export function SwitchProfile(name) {
+    return $Call.ByID(3869054282, name);
+}
+
 // Private type creation functions
 const $$createType0 = $models.UpdateInfo.createFrom;
 const $$createType1 = $Create.Nullable($$createType0);
-const $$createType2 = $models.AppConfig.createFrom;
+const $$createType2 = $models.WeatherAlert.createFrom;
 const $$createType3 = $Create.Nullable($$createType2);
+const $$createType4 = $models.Appearance.createFrom;
+const $$createType5 = $Create.Nullable($$createType4);
+const $$createType6 = $models.BuildInfo.createFrom;
+const $$createType7 = $models.AppConfig.createFrom;
+const $$createType8 = $Create.Nullable($$createType7);
+const $$createType9 = $models.EffectiveSetting.createFrom;
+const $$createType10 = $Create.Nullable($$createType9);
+const $$createType11 = $Create.Array($$createType9);
+const $$createType12 = $models.UpdateCheckResult.createFrom;
+const $$createType13 = $Create.Nullable($$createType12);
+const $$createType14 = $Create.Map($Create.Any, $Create.Any);
+const $$createType15 = $models.StartupVerification.createFrom;
+const $$createType16 = $Create.Nullable($$createType15);
+const $$createType17 = $Create.Array($Create.Any);
+const $$createType18 = $models.StartupStatus.createFrom;
+const $$createType19 = $Create.Nullable($$createType18);
@@ -10,8 +10,34 @@ export {
 };
 
 export {
+    AlertConfig,
     AppConfig,
+    Appearance,
+    BuildInfo,
+    ColorScale,
+    ColorStop,
+    EffectiveSetting,
     ForecastDay,
+    LaunchOptions,
+    StartupConfig,
+    StartupStatus,
+    StartupVerification,
+    TemperatureBand,
+    TrayIconConfig,
+    TrayIconPalette,
+    UpdateCheckResult,
+    UpdateConfig,
     UpdateInfo,
-    WeatherData
+    UpdateProgress,
+    WeatherAlert,
+    WeatherData,
+    WindowPosition
 } from "./models.js";
+
+import * as $models from "./models.js";
+
+/**
+ * SecretRef references a secret by name. Config fields hold a SecretRef
+ * instead of the secret value so config.json never contains credentials.
+ * @typedef {$models.SecretRef} SecretRef
+ */
@@ -6,132 +6,1182 @@
 // @ts-ignore: Unused imports
 import { Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as time$0 from "../time/models.js";
+
+/**
+ * AlertConfig sets when temperature alerts are raised
+ */
+export class AlertConfig {
+    /**
+     * Creates a new AlertConfig instance.
+     * @param {Partial<AlertConfig>} [$$source = {}] - The source object to create the AlertConfig.
+     */
+    constructor($$source = {}) {
+        if (!("extremeCold" in $$source)) {
+            /**
+             * ExtremeCold raises an alert at or below this temperature in °C
+             * @member
+             * @type {number}
+             */
+            this["extremeCold"] = 0;
+        }
+        if (!("extremeHeat" in $$source)) {
+            /**
+             * ExtremeHeat raises an alert at or above this temperature in °C
+             * @member
+             * @type {number}
+             */
+            this["extremeHeat"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AlertConfig instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {AlertConfig}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new AlertConfig(/** @type {Partial<AlertConfig>} */($$parsedSource));
+    }
+}
+
+/**
+ * AppConfig represents the application configuration
+ */
+export class AppConfig {
+    /**
+     * Creates a new AppConfig instance.
+     * @param {Partial<AppConfig>} [$$source = {}] - The source object to create the AppConfig.
+     */
+    constructor($$source = {}) {
+        if (!("theme" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["theme"] = "";
+        }
+        if (!("language" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["language"] = "";
+        }
+        if (!("windowWidth" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["windowWidth"] = 0;
+        }
+        if (!("windowHeight" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["windowHeight"] = 0;
+        }
+        if (!("customSettings" in $$source)) {
+            /**
+             * @member
+             * @type {{ [_: string]: any }}
+             */
+            this["customSettings"] = {};
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["activeProfile"] = undefined;
+        }
+        if (!("secretsBackend" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["secretsBackend"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {SecretRef | undefined}
+             */
+            this["githubToken"] = undefined;
+        }
+        if (!("trayIcon" in $$source)) {
+            /**
+             * @member
+             * @type {TrayIconConfig}
+             */
+            this["trayIcon"] = (new TrayIconConfig());
+        }
+        if (!("trayLabel" in $$source)) {
+            /**
+             * TrayLabel is the tray label template, e.g. "{location}: {temp}{unit}"
+             * @member
+             * @type {string}
+             */
+            this["trayLabel"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * TrayTooltip is the tooltip template; empty uses the default for the
+             * configured language
+             * @member
+             * @type {string | undefined}
+             */
+            this["trayTooltip"] = undefined;
+        }
+        if (!("updates" in $$source)) {
+            /**
+             * @member
+             * @type {UpdateConfig}
+             */
+            this["updates"] = (new UpdateConfig());
+        }
+        if (!("startup" in $$source)) {
+            /**
+             * Startup configures launching at login
+             * @member
+             * @type {StartupConfig}
+             */
+            this["startup"] = (new StartupConfig());
+        }
+        if (!("alerts" in $$source)) {
+            /**
+             * Alerts sets the temperatures that raise an alert
+             * @member
+             * @type {AlertConfig}
+             */
+            this["alerts"] = (new AlertConfig());
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * WindowPositions remembers the window position per screen ID
+             * @member
+             * @type {{ [_: string]: WindowPosition } | undefined}
+             */
+            this["windowPositions"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AppConfig instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {AppConfig}
+     */
+    static createFrom($$source = {}) {
+        const $$createField4_0 = $$createType0;
+        const $$createField8_0 = $$createType1;
+        const $$createField11_0 = $$createType2;
+        const $$createField12_0 = $$createType3;
+        const $$createField13_0 = $$createType4;
+        const $$createField14_0 = $$createType6;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("customSettings" in $$parsedSource) {
+            $$parsedSource["customSettings"] = $$createField4_0($$parsedSource["customSettings"]);
+        }
+        if ("trayIcon" in $$parsedSource) {
+            $$parsedSource["trayIcon"] = $$createField8_0($$parsedSource["trayIcon"]);
+        }
+        if ("updates" in $$parsedSource) {
+            $$parsedSource["updates"] = $$createField11_0($$parsedSource["updates"]);
+        }
+        if ("startup" in $$parsedSource) {
+            $$parsedSource["startup"] = $$createField12_0($$parsedSource["startup"]);
+        }
+        if ("alerts" in $$parsedSource) {
+            $$parsedSource["alerts"] = $$createField13_0($$parsedSource["alerts"]);
+        }
+        if ("windowPositions" in $$parsedSource) {
+            $$parsedSource["windowPositions"] = $$createField14_0($$parsedSource["windowPositions"]);
+        }
+        return new AppConfig(/** @type {Partial<AppConfig>} */($$parsedSource));
+    }
+}
+
+/**
+ * Appearance is the resolved theme and language sent to the frontend
+ */
+export class Appearance {
+    /**
+     * Creates a new Appearance instance.
+     * @param {Partial<Appearance>} [$$source = {}] - The source object to create the Appearance.
+     */
+    constructor($$source = {}) {
+        if (!("theme" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["theme"] = "";
+        }
+        if (!("language" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["language"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Appearance instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Appearance}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Appearance(/** @type {Partial<Appearance>} */($$parsedSource));
+    }
+}
+
+/**
+ * BuildInfo describes the running build
+ */
+export class BuildInfo {
+    /**
+     * Creates a new BuildInfo instance.
+     * @param {Partial<BuildInfo>} [$$source = {}] - The source object to create the BuildInfo.
+     */
+    constructor($$source = {}) {
+        if (!("version" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["version"] = "";
+        }
+        if (!("commit" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["commit"] = "";
+        }
+        if (!("buildDate" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["buildDate"] = "";
+        }
+        if (!("modified" in $$source)) {
+            /**
+             * built from a tree with uncommitted changes
+             * @member
+             * @type {boolean}
+             */
+            this["modified"] = false;
+        }
+        if (!("goVersion" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["goVersion"] = "";
+        }
+        if (!("wailsVersion" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["wailsVersion"] = "";
+        }
+        if (!("platform" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["platform"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new BuildInfo instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {BuildInfo}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new BuildInfo(/** @type {Partial<BuildInfo>} */($$parsedSource));
+    }
+}
+
+/**
+ * ColorScale maps temperatures to colours by interpolating between stops
+ */
+export class ColorScale {
+    /**
+     * Creates a new ColorScale instance.
+     * @param {Partial<ColorScale>} [$$source = {}] - The source object to create the ColorScale.
+     */
+    constructor($$source = {}) {
+        if (/** @type {any} */(false)) {
+            /**
+             * Stops are in ascending temperature order; temperatures outside the
+             * first and last stop take the colour of that stop
+             * @member
+             * @type {ColorStop[] | undefined}
+             */
+            this["stops"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * ConditionBlend mixes in the condition colour, from 0 (temperature
+             * only) to 1 (condition only)
+             * @member
+             * @type {number | undefined}
+             */
+            this["conditionBlend"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ColorScale instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ColorScale}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType8;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("stops" in $$parsedSource) {
+            $$parsedSource["stops"] = $$createField0_0($$parsedSource["stops"]);
+        }
+        return new ColorScale(/** @type {Partial<ColorScale>} */($$parsedSource));
+    }
+}
+
+/**
+ * ColorStop is a "#rrggbb" colour at a temperature in °C, whatever the
+ * configured temperatureUnit
+ */
+export class ColorStop {
+    /**
+     * Creates a new ColorStop instance.
+     * @param {Partial<ColorStop>} [$$source = {}] - The source object to create the ColorStop.
+     */
+    constructor($$source = {}) {
+        if (!("temperature" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["temperature"] = 0;
+        }
+        if (!("color" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["color"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ColorStop instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ColorStop}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ColorStop(/** @type {Partial<ColorStop>} */($$parsedSource));
+    }
+}
+
+/**
+ * EffectiveSetting reports the value a setting resolved to and where it came from
+ */
+export class EffectiveSetting {
+    /**
+     * Creates a new EffectiveSetting instance.
+     * @param {Partial<EffectiveSetting>} [$$source = {}] - The source object to create the EffectiveSetting.
+     */
+    constructor($$source = {}) {
+        if (!("key" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["key"] = "";
+        }
+        if (!("value" in $$source)) {
+            /**
+             * @member
+             * @type {any}
+             */
+            this["value"] = null;
+        }
+        if (!("source" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["source"] = "";
+        }
+        if (!("envVar" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["envVar"] = "";
+        }
+        if (!("flag" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["flag"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new EffectiveSetting instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {EffectiveSetting}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new EffectiveSetting(/** @type {Partial<EffectiveSetting>} */($$parsedSource));
+    }
+}
+
+/**
+ * ForecastDay represents a single day forecast
+ */
+export class ForecastDay {
+    /**
+     * Creates a new ForecastDay instance.
+     * @param {Partial<ForecastDay>} [$$source = {}] - The source object to create the ForecastDay.
+     */
+    constructor($$source = {}) {
+        if (!("date" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["date"] = "";
+        }
+        if (!("dayOfWeek" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["dayOfWeek"] = "";
+        }
+        if (!("maxTemp" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["maxTemp"] = 0;
+        }
+        if (!("minTemp" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["minTemp"] = 0;
+        }
+        if (!("condition" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["condition"] = "";
+        }
+        if (!("weatherCode" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["weatherCode"] = 0;
+        }
+        if (!("icon" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["icon"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ForecastDay instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ForecastDay}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ForecastDay(/** @type {Partial<ForecastDay>} */($$parsedSource));
+    }
+}
+
+/**
+ * LaunchOptions holds the command-line options parsed in main
+ */
+export class LaunchOptions {
+    /**
+     * Creates a new LaunchOptions instance.
+     * @param {Partial<LaunchOptions>} [$$source = {}] - The source object to create the LaunchOptions.
+     */
+    constructor($$source = {}) {
+        if (!("Profile" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["Profile"] = "";
+        }
+        if (!("Overrides" in $$source)) {
+            /**
+             * @member
+             * @type {{ [_: string]: string }}
+             */
+            this["Overrides"] = {};
+        }
+        if (!("Args" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["Args"] = [];
+        }
+        if (!("Minimized" in $$source)) {
+            /**
+             * Minimized starts with only the tray icon, as autostart entries do
+             * @member
+             * @type {boolean}
+             */
+            this["Minimized"] = false;
+        }
+        if (!("LaunchDelay" in $$source)) {
+            /**
+             * LaunchDelay is the number of seconds to wait before starting
+             * @member
+             * @type {number}
+             */
+            this["LaunchDelay"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LaunchOptions instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {LaunchOptions}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType9;
+        const $$createField2_0 = $$createType10;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("Overrides" in $$parsedSource) {
+            $$parsedSource["Overrides"] = $$createField1_0($$parsedSource["Overrides"]);
+        }
+        if ("Args" in $$parsedSource) {
+            $$parsedSource["Args"] = $$createField2_0($$parsedSource["Args"]);
+        }
+        return new LaunchOptions(/** @type {Partial<LaunchOptions>} */($$parsedSource));
+    }
+}
+
+/**
+ * SecretRef references a secret by name. Config fields hold a SecretRef
+ * instead of the secret value so config.json never contains credentials.
+ * @typedef {string} SecretRef
+ */
+
+/**
+ * StartupConfig controls how the app is launched at login. Changes apply
+ * the next time startup is enabled.
+ */
+export class StartupConfig {
+    /**
+     * Creates a new StartupConfig instance.
+     * @param {Partial<StartupConfig>} [$$source = {}] - The source object to create the StartupConfig.
+     */
+    constructor($$source = {}) {
+        if (!("mechanism" in $$source)) {
+            /**
+             * Mechanism is the Linux autostart method: xdg for a desktop entry in
+             * ~/.config/autostart, or systemd for a user service that is restarted
+             * if it crashes. Other platforms have a single mechanism.
+             * @member
+             * @type {string}
+             */
+            this["mechanism"] = "";
+        }
+        if (!("startMinimized" in $$source)) {
+            /**
+             * StartMinimized starts with only the tray icon
+             * @member
+             * @type {boolean}
+             */
+            this["startMinimized"] = false;
+        }
+        if (!("delay" in $$source)) {
+            /**
+             * Delay waits this many seconds after login before starting
+             * @member
+             * @type {number}
+             */
+            this["delay"] = 0;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * Args are extra arguments, e.g. ["--profile", "work"]
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["args"] = undefined;
+        }
+        if (!("repairStale" in $$source)) {
+            /**
+             * RepairStale re-registers an entry whose executable no longer exists
+             * for the running executable at launch
+             * @member
+             * @type {boolean}
+             */
+            this["repairStale"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new StartupConfig instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {StartupConfig}
+     */
+    static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType10;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("args" in $$parsedSource) {
+            $$parsedSource["args"] = $$createField3_0($$parsedSource["args"]);
+        }
+        return new StartupConfig(/** @type {Partial<StartupConfig>} */($$parsedSource));
+    }
+}
+
+/**
+ * StartupStatus describes the registered startup entry
+ */
+export class StartupStatus {
+    /**
+     * Creates a new StartupStatus instance.
+     * @param {Partial<StartupStatus>} [$$source = {}] - The source object to create the StartupStatus.
+     */
+    constructor($$source = {}) {
+        if (!("enabled" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["enabled"] = false;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["mechanism"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["exePath"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["args"] = undefined;
+        }
+        if (!("current" in $$source)) {
+            /**
+             * Current reports whether the entry starts the running executable
+             * @member
+             * @type {boolean}
+             */
+            this["current"] = false;
+        }
+        if (!("missing" in $$source)) {
+            /**
+             * Missing reports that the registered executable no longer exists
+             * @member
+             * @type {boolean}
+             */
+            this["missing"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new StartupStatus instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {StartupStatus}
+     */
+    static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType10;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("args" in $$parsedSource) {
+            $$parsedSource["args"] = $$createField3_0($$parsedSource["args"]);
+        }
+        return new StartupStatus(/** @type {Partial<StartupStatus>} */($$parsedSource));
+    }
+}
+
+/**
+ * StartupVerification is the result of checking the startup entry against
+ * the running executable
+ */
+export class StartupVerification {
+    /**
+     * Creates a new StartupVerification instance.
+     * @param {Partial<StartupVerification>} [$$source = {}] - The source object to create the StartupVerification.
+     */
+    constructor($$source = {}) {
+        if (!("checkedAt" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time}
+             */
+            this["checkedAt"] = null;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {StartupStatus | null | undefined}
+             */
+            this["status"] = undefined;
+        }
+        if (!("stale" in $$source)) {
+            /**
+             * Stale reports an entry that does not start the running executable
+             * @member
+             * @type {boolean}
+             */
+            this["stale"] = false;
+        }
+        if (!("repaired" in $$source)) {
+            /**
+             * Repaired reports that the entry was re-registered for the running
+             * executable
+             * @member
+             * @type {boolean}
+             */
+            this["repaired"] = false;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["error"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new StartupVerification instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {StartupVerification}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType12;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("status" in $$parsedSource) {
+            $$parsedSource["status"] = $$createField1_0($$parsedSource["status"]);
+        }
+        return new StartupVerification(/** @type {Partial<StartupVerification>} */($$parsedSource));
+    }
+}
+
+/**
+ * TemperatureBand colours temperatures from Min °C up to the next band.
+ * Bands are always in °C, also when temperatureUnit is fahrenheit, so a
+ * palette keeps working when the unit is switched.
+ */
+export class TemperatureBand {
+    /**
+     * Creates a new TemperatureBand instance.
+     * @param {Partial<TemperatureBand>} [$$source = {}] - The source object to create the TemperatureBand.
+     */
+    constructor($$source = {}) {
+        if (!("min" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["min"] = 0;
+        }
+        if (!("color" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["color"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TemperatureBand instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {TemperatureBand}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TemperatureBand(/** @type {Partial<TemperatureBand>} */($$parsedSource));
+    }
+}
+
+/**
+ * TrayIconConfig selects how the tray icon is drawn
+ */
+export class TrayIconConfig {
+    /**
+     * Creates a new TrayIconConfig instance.
+     * @param {Partial<TrayIconConfig>} [$$source = {}] - The source object to create the TrayIconConfig.
+     */
+    constructor($$source = {}) {
+        if (!("style" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["style"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * ShowDegree appends a degree sign to the temperature
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["showDegree"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * TrueMinus draws negative values with U+2212 MINUS SIGN instead of a
+             * hyphen, which is wider and sits at the height of the digits
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["trueMinus"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * FontFile is a TTF or OTF font for the temperature; the embedded Go
+             * bold font is used when empty or unreadable
+             * @member
+             * @type {string | undefined}
+             */
+            this["fontFile"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * TextColor is a "#rrggbb" colour; by default the text contrasts with
+             * the background
+             * @member
+             * @type {string | undefined}
+             */
+            this["textColor"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * Background is the shape behind the number: circle, rounded or none
+             * @member
+             * @type {string | undefined}
+             */
+            this["background"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {TrayIconPalette | null | undefined}
+             */
+            this["palette"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * ColorScale configures the temperature style
+             * @member
+             * @type {ColorScale | null | undefined}
+             */
+            this["colorScale"] = undefined;
+        }
+        if (!("animationFps" in $$source)) {
+            /**
+             * AnimationFPS is the frame rate of the refresh and alert animations;
+             * 0 disables them
+             * @member
+             * @type {number}
+             */
+            this["animationFps"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrayIconConfig instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {TrayIconConfig}
+     */
+    static createFrom($$source = {}) {
+        const $$createField6_0 = $$createType14;
+        const $$createField7_0 = $$createType16;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("palette" in $$parsedSource) {
+            $$parsedSource["palette"] = $$createField6_0($$parsedSource["palette"]);
+        }
+        if ("colorScale" in $$parsedSource) {
+            $$parsedSource["colorScale"] = $$createField7_0($$parsedSource["colorScale"]);
+        }
+        return new TrayIconConfig(/** @type {Partial<TrayIconConfig>} */($$parsedSource));
+    }
+}
+
 /**
- * AppConfig represents the application configuration
+ * TrayIconPalette maps conditions or temperature bands to background colours.
+ * Colours are "#rrggbb" or "#rrggbbaa".
  */
-export class AppConfig {
+export class TrayIconPalette {
     /**
-     * Creates a new AppConfig instance.
-     * @param {Partial<AppConfig>} [$$source = {}] - The source object to create the AppConfig.
+     * Creates a new TrayIconPalette instance.
+     * @param {Partial<TrayIconPalette>} [$$source = {}] - The source object to create the TrayIconPalette.
      */
     constructor($$source = {}) {
-        if (!("theme" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
+             * Conditions is keyed by condition category, e.g. "rain", with an
+             * optional ".night" suffix for the night variant, e.g. "clear.night"
              * @member
-             * @type {string}
+             * @type {{ [_: string]: string } | undefined}
              */
-            this["theme"] = "";
+            this["conditions"] = undefined;
         }
-        if (!("language" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
+             * TemperatureBands take precedence over Conditions when set
              * @member
-             * @type {string}
+             * @type {TemperatureBand[] | undefined}
              */
-            this["language"] = "";
+            this["temperatureBands"] = undefined;
         }
-        if (!("windowWidth" in $$source)) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrayIconPalette instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {TrayIconPalette}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType9;
+        const $$createField1_0 = $$createType18;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("conditions" in $$parsedSource) {
+            $$parsedSource["conditions"] = $$createField0_0($$parsedSource["conditions"]);
+        }
+        if ("temperatureBands" in $$parsedSource) {
+            $$parsedSource["temperatureBands"] = $$createField1_0($$parsedSource["temperatureBands"]);
+        }
+        return new TrayIconPalette(/** @type {Partial<TrayIconPalette>} */($$parsedSource));
+    }
+}
+
+/**
+ * UpdateCheckResult is the outcome of the last update check
+ */
+export class UpdateCheckResult {
+    /**
+     * Creates a new UpdateCheckResult instance.
+     * @param {Partial<UpdateCheckResult>} [$$source = {}] - The source object to create the UpdateCheckResult.
+     */
+    constructor($$source = {}) {
+        if (!("checkedAt" in $$source)) {
             /**
              * @member
-             * @type {number}
+             * @type {time$0.Time}
              */
-            this["windowWidth"] = 0;
+            this["checkedAt"] = null;
         }
-        if (!("windowHeight" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
              * @member
-             * @type {number}
+             * @type {UpdateInfo | null | undefined}
              */
-            this["windowHeight"] = 0;
+            this["info"] = undefined;
         }
-        if (!("customSettings" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
              * @member
-             * @type {{ [_: string]: any }}
+             * @type {string | undefined}
              */
-            this["customSettings"] = {};
+            this["error"] = undefined;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new AppConfig instance from a string or object.
+     * Creates a new UpdateCheckResult instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {AppConfig}
+     * @returns {UpdateCheckResult}
      */
     static createFrom($$source = {}) {
-        const $$createField4_0 = $$createType0;
+        const $$createField1_0 = $$createType20;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        if ("customSettings" in $$parsedSource) {
-            $$parsedSource["customSettings"] = $$createField4_0($$parsedSource["customSettings"]);
+        if ("info" in $$parsedSource) {
+            $$parsedSource["info"] = $$createField1_0($$parsedSource["info"]);
         }
-        return new AppConfig(/** @type {Partial<AppConfig>} */($$parsedSource));
+        return new UpdateCheckResult(/** @type {Partial<UpdateCheckResult>} */($$parsedSource));
     }
 }
 
 /**
- * ForecastDay represents a single day forecast
+ * UpdateConfig controls where updates come from and which are offered
  */
-export class ForecastDay {
+export class UpdateConfig {
     /**
-     * Creates a new ForecastDay instance.
-     * @param {Partial<ForecastDay>} [$$source = {}] - The source object to create the ForecastDay.
+     * Creates a new UpdateConfig instance.
+     * @param {Partial<UpdateConfig>} [$$source = {}] - The source object to create the UpdateConfig.
      */
     constructor($$source = {}) {
-        if (!("date" in $$source)) {
+        if (!("channel" in $$source)) {
             /**
+             * Channel is the least stable channel offered: stable, beta or nightly
              * @member
              * @type {string}
              */
-            this["date"] = "";
+            this["channel"] = "";
         }
-        if (!("dayOfWeek" in $$source)) {
+        if (!("source" in $$source)) {
             /**
+             * Source lists the releases: github, manifest or directory
              * @member
              * @type {string}
              */
-            this["dayOfWeek"] = "";
+            this["source"] = "";
         }
-        if (!("maxTemp" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
+             * Repository is the GitHub repository as owner/name
              * @member
-             * @type {number}
+             * @type {string | undefined}
              */
-            this["maxTemp"] = 0;
+            this["repository"] = undefined;
         }
-        if (!("minTemp" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
+             * APIURL replaces https://api.github.com, e.g. with a local fake
+             * release server for testing
              * @member
-             * @type {number}
+             * @type {string | undefined}
              */
-            this["minTemp"] = 0;
+            this["apiUrl"] = undefined;
         }
-        if (!("condition" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
+             * ManifestURL is the release manifest for the manifest source
              * @member
-             * @type {string}
+             * @type {string | undefined}
              */
-            this["condition"] = "";
+            this["manifestUrl"] = undefined;
         }
-        if (!("icon" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
+             * Directory holds manifest.json and the assets for the directory source
              * @member
-             * @type {string}
+             * @type {string | undefined}
              */
-            this["icon"] = "";
+            this["directory"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * AssetFormats orders the download formats by preference, e.g.
+             * ["appimage", "tar.gz"]; empty uses the platform default
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["assetFormats"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * IgnoredVersions are never offered
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["ignoredVersions"] = undefined;
+        }
+        if (!("autoCheck" in $$source)) {
+            /**
+             * AutoCheck checks for updates in the background every
+             * CheckIntervalHours
+             * @member
+             * @type {boolean}
+             */
+            this["autoCheck"] = false;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * CheckIntervalHours is the time between background checks; 0 uses
+             * CheckInterval
+             * @member
+             * @type {number | undefined}
+             */
+            this["checkIntervalHours"] = undefined;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new ForecastDay instance from a string or object.
+     * Creates a new UpdateConfig instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {ForecastDay}
+     * @returns {UpdateConfig}
      */
     static createFrom($$source = {}) {
+        const $$createField6_0 = $$createType10;
+        const $$createField7_0 = $$createType10;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new ForecastDay(/** @type {Partial<ForecastDay>} */($$parsedSource));
+        if ("assetFormats" in $$parsedSource) {
+            $$parsedSource["assetFormats"] = $$createField6_0($$parsedSource["assetFormats"]);
+        }
+        if ("ignoredVersions" in $$parsedSource) {
+            $$parsedSource["ignoredVersions"] = $$createField7_0($$parsedSource["ignoredVersions"]);
+        }
+        return new UpdateConfig(/** @type {Partial<UpdateConfig>} */($$parsedSource));
     }
 }
 
@@ -172,6 +1222,14 @@ export class UpdateInfo {
              */
             this["description"] = "";
         }
+        if (!("descriptionHtml" in $$source)) {
+            /**
+             * DescriptionHTML is Description rendered from Markdown and sanitised
+             * @member
+             * @type {string}
+             */
+            this["descriptionHtml"] = "";
+        }
         if (!("available" in $$source)) {
             /**
              * @member
@@ -179,6 +1237,53 @@ export class UpdateInfo {
              */
             this["available"] = false;
         }
+        if (!("assetName" in $$source)) {
+            /**
+             * AssetName is the file name of the download, as listed in the
+             * checksums file published at ChecksumsURL
+             * @member
+             * @type {string}
+             */
+            this["assetName"] = "";
+        }
+        if (!("checksumsUrl" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["checksumsUrl"] = "";
+        }
+        if (!("signatureUrl" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["signatureUrl"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["channel"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * Ignored is set when the latest version is on the ignore list
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["ignored"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * Error explains why an available update cannot be installed, e.g. a
+             * missing or invalid signature
+             * @member
+             * @type {string | undefined}
+             */
+            this["error"] = undefined;
+        }
 
         Object.assign(this, $$source);
     }
@@ -194,6 +1299,98 @@ export class UpdateInfo {
     }
 }
 
+/**
+ * UpdateProgress is emitted as the "updateProgress" event while an update
+ * is installed
+ */
+export class UpdateProgress {
+    /**
+     * Creates a new UpdateProgress instance.
+     * @param {Partial<UpdateProgress>} [$$source = {}] - The source object to create the UpdateProgress.
+     */
+    constructor($$source = {}) {
+        if (!("phase" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["phase"] = "";
+        }
+        if (!("downloaded" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["downloaded"] = 0;
+        }
+        if (!("total" in $$source)) {
+            /**
+             * -1 when the size is unknown
+             * @member
+             * @type {number}
+             */
+            this["total"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new UpdateProgress instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {UpdateProgress}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new UpdateProgress(/** @type {Partial<UpdateProgress>} */($$parsedSource));
+    }
+}
+
+/**
+ * WeatherAlert is a severe weather warning for the current conditions
+ */
+export class WeatherAlert {
+    /**
+     * Creates a new WeatherAlert instance.
+     * @param {Partial<WeatherAlert>} [$$source = {}] - The source object to create the WeatherAlert.
+     */
+    constructor($$source = {}) {
+        if (!("kind" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["kind"] = "";
+        }
+        if (!("message" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["message"] = "";
+        }
+        if (!("acknowledged" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["acknowledged"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WeatherAlert instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {WeatherAlert}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new WeatherAlert(/** @type {Partial<WeatherAlert>} */($$parsedSource));
+    }
+}
+
 /**
  * WeatherData represents the weather information
  */
@@ -224,6 +1421,28 @@ export class WeatherData {
              */
             this["feelsLike"] = 0;
         }
+        if (!("todayMax" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["todayMax"] = 0;
+        }
+        if (!("todayMin" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["todayMin"] = 0;
+        }
+        if (!("rainChance" in $$source)) {
+            /**
+             * next hour, in percent
+             * @member
+             * @type {number}
+             */
+            this["rainChance"] = 0;
+        }
         if (!("condition" in $$source)) {
             /**
              * @member
@@ -231,6 +1450,20 @@ export class WeatherData {
              */
             this["condition"] = "";
         }
+        if (!("weatherCode" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["weatherCode"] = 0;
+        }
+        if (!("isDay" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["isDay"] = false;
+        }
         if (!("description" in $$source)) {
             /**
              * @member
@@ -283,16 +1516,74 @@ export class WeatherData {
      * @returns {WeatherData}
      */
     static createFrom($$source = {}) {
-        const $$createField9_0 = $$createType2;
+        const $$createField14_0 = $$createType22;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("forecast" in $$parsedSource) {
-            $$parsedSource["forecast"] = $$createField9_0($$parsedSource["forecast"]);
+            $$parsedSource["forecast"] = $$createField14_0($$parsedSource["forecast"]);
         }
         return new WeatherData(/** @type {Partial<WeatherData>} */($$parsedSource));
     }
 }
 
+/**
+ * WindowPosition is a remembered window position on one screen
+ */
+export class WindowPosition {
+    /**
+     * Creates a new WindowPosition instance.
+     * @param {Partial<WindowPosition>} [$$source = {}] - The source object to create the WindowPosition.
+     */
+    constructor($$source = {}) {
+        if (!("x" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["x"] = 0;
+        }
+        if (!("y" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["y"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WindowPosition instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {WindowPosition}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new WindowPosition(/** @type {Partial<WindowPosition>} */($$parsedSource));
+    }
+}
+
 // Private type creation functions
 const $$createType0 = $Create.Map($Create.Any, $Create.Any);
-const $$createType1 = ForecastDay.createFrom;
-const $$createType2 = $Create.Array($$createType1);
+const $$createType1 = TrayIconConfig.createFrom;
+const $$createType2 = UpdateConfig.createFrom;
+const $$createType3 = StartupConfig.createFrom;
+const $$createType4 = AlertConfig.createFrom;
+const $$createType5 = WindowPosition.createFrom;
+const $$createType6 = $Create.Map($Create.Any, $$createType5);
+const $$createType7 = ColorStop.createFrom;
+const $$createType8 = $Create.Array($$createType7);
+const $$createType9 = $Create.Map($Create.Any, $Create.Any);
+const $$createType10 = $Create.Array($Create.Any);
+const $$createType11 = StartupStatus.createFrom;
+const $$createType12 = $Create.Nullable($$createType11);
+const $$createType13 = TrayIconPalette.createFrom;
+const $$createType14 = $Create.Nullable($$createType13);
+const $$createType15 = ColorScale.createFrom;
+const $$createType16 = $Create.Nullable($$createType15);
+const $$createType17 = TemperatureBand.createFrom;
+const $$createType18 = $Create.Array($$createType17);
+const $$createType19 = UpdateInfo.createFrom;
+const $$createType20 = $Create.Nullable($$createType19);
+const $$createType21 = ForecastDay.createFrom;
+const $$createType22 = $Create.Array($$createType21);
@@ -15,6 +15,16 @@ import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Cr
 // @ts-ignore: Unused imports
 import * as $models from "./models.js";
 
+/**
+ * ExportTrayIcons writes the current tray icon at every size to dir, named
+ * tray-<size>.png and tray-<size>@2x.png
+ * @param {string} dir
+ * @returns {$CancellablePromise<void>}
+ */
+export function ExportTrayIcons(dir) {
+    return $Call.ByID(2997774039, dir);
+}
+
 /**
  * GetStoredLocation retrieves the stored weather location
  * @returns {$CancellablePromise<string>}
@@ -23,6 +33,17 @@ export function GetStoredLocation() {
     return $Call.ByID(3432447403);
 }
 
+/**
+ * GetTrayLabelPlaceholders returns the placeholder names a tray label
+ * template can use
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function GetTrayLabelPlaceholders() {
+    return $Call.ByID(4159799641).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType0($result);
+    }));
+}
+
 /**
  * GetWeather fetches weather data for a given location from Open-Meteo API
  * @param {string} location
@@ -30,10 +51,29 @@ export function GetStoredLocation() {
  */
 export function GetWeather(location) {
     return $Call.ByID(1811001601, location).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType1($result);
+        return $$createType2($result);
     }));
 }
 
+/**
+ * PreviewTrayLabel formats a tray label template with the latest weather
+ * @param {string} template
+ * @returns {$CancellablePromise<string>}
+ */
+export function PreviewTrayLabel(template) {
+    return $Call.ByID(3595393587, template);
+}
+
+/**
+ * PreviewTrayTooltip formats a tooltip template with the latest weather,
+ * truncated as it would be on this platform
+ * @param {string} template
+ * @returns {$CancellablePromise<string>}
+ */
+export function PreviewTrayTooltip(template) {
+    return $Call.ByID(2197036346, template);
+}
+
 /**
  * RefreshWeather refreshes the weather data and updates tray icon
  * @param {string} location
@@ -41,10 +81,20 @@ export function GetWeather(location) {
  */
 export function RefreshWeather(location) {
     return $Call.ByID(2131631672, location).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType1($result);
+        return $$createType2($result);
     }));
 }
 
+/**
+ * SetRefreshStateFunc sets the function told when a weather fetch starts and
+ * ends. It must not block.
+ * @param {any} stateFunc
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetRefreshStateFunc(stateFunc) {
+    return $Call.ByID(648544225, stateFunc);
+}
+
 /**
  * SetTrayUpdateFunc sets the function to update the tray icon
  * @param {any} updateFunc
@@ -64,5 +114,6 @@ export function UpdateLocation(location) {
 }
 
 // Private type creation functions
-const $$createType0 = $models.WeatherData.createFrom;
-const $$createType1 = $Create.Nullable($$createType0);
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = $models.WeatherData.createFrom;
+const $$createType2 = $Create.Nullable($$createType1);
@@ -8,6 +8,10 @@ function App() {
   const [newLocation, setNewLocation] = useState('');
   const [loading, setLoading] = useState(true);
   const [weatherIcons, setWeatherIcons] = useState({});
+  const [messages, setMessages] = useState({});
+
+  // Translate a message ID using the catalogue provided by the Go side
+  const t = (key, fallback) => messages[key] || fallback;
 
   // Load SVG icons
   const loadIcon = async (iconCode) => {
@@ -106,24 +110,58 @@ function App() {
     }
   };
 
+  // Apply the configured theme and language, and follow live changes
+  const applyAppearance = (appearance) => {
+    if (!appearance) return;
+    document.documentElement.dataset.theme = appearance.theme;
+    document.documentElement.lang = appearance.language;
+    import('../bindings/weatherApp/app')
+      .then(({ GetMessages }) => GetMessages())
+      .then((catalogue) => setMessages(catalogue || {}))
+      .catch((error) => console.error('Failed to load messages:', error));
+  };
+
   useEffect(() => {
     getStoredLocation();
+
+    let unsubscribe;
+    Promise.all([import('../bindings/weatherApp/app'), import('@wailsio/runtime')])
+      .then(([{ GetAppearance }, { Events }]) => {
+        GetAppearance().then(applyAppearance);
+        unsubscribe = Events.On('appearanceChanged', (event) => applyAppearance(event.data));
+      })
+      .catch((error) => console.error('Failed to load appearance:', error));
+
+    return () => unsubscribe && unsubscribe();
   }, []);
 
   useEffect(() => {
-    if (location) {
-      loadWeather();
-      // Refresh weather every 5 minutes
-      const interval = setInterval(loadWeather, 300000);
-      return () => clearInterval(interval);
-    }
+    if (!location) return;
+    loadWeather();
+
+    // Refresh weather every updateInterval seconds, 5 minutes by default
+    let interval;
+    let cancelled = false;
+    import('../bindings/weatherApp/app')
+      .then(({ GetSetting }) => GetSetting('updateInterval'))
+      .catch(() => null)
+      .then((seconds) => {
+        if (cancelled) return;
+        const delay = (seconds >= 60 ? seconds : 300) * 1000;
+        interval = setInterval(loadWeather, delay);
+      });
+
+    return () => {
+      cancelled = true;
+      clearInterval(interval);
+    };
   }, [location]);
 
   if (loading) {
     return (
       <div className="weather-app loading">
         <div className="spinner"></div>
-        <p>Loading weather...</p>
+        <p>{t('ui.loading', 'Loading weather...')}</p>
       </div>
     );
   }
@@ -131,7 +169,7 @@ function App() {
   if (!weather) {
     return (
       <div className="weather-app error">
-        <p>Unable to load weather data</p>
+        <p>{t('ui.loadError', 'Unable to load weather data')}</p>
       </div>
     );
   }
@@ -167,13 +205,14 @@ function App() {
             </div>
           )}
         </div>
-        <button className="minimize-btn" onClick={handleMinimize} title="Hide to tray">
+        <button className="minimize-btn" onClick={handleMinimize} title={t('ui.hideToTray', 'Hide to tray')}>
           ✕
         </button>
       </div>
       <div className="weather-header">
         <div className="last-updated">
-          Updated: {new Date(weather.lastUpdated).toLocaleTimeString()}
+          {t('ui.updated', 'Updated')}:{' '}
+          {new Date(weather.lastUpdated).toLocaleTimeString(document.documentElement.lang || undefined)}
         </div>
       </div>
 
@@ -183,22 +222,30 @@ function App() {
           <span className="temp-unit">C</span>
         </div>
         <div className="condition">{weather.condition}</div>
-        <div className="feels-like">Feels like {Math.round(weather.feelsLike)}°C</div>
+        <div className="feels-like">
+          {t('ui.feelsLike', 'Feels like')} {Math.round(weather.feelsLike)}°C
+        </div>
       </div>
 
       <div className="weather-details">
         <div className="detail-item">
-          <span className="detail-label">Humidity</span>
+          <span className="detail-label">{t('ui.humidity', 'Humidity')}</span>
           <span className="detail-value">{weather.humidity}%</span>
         </div>
         <div className="detail-item">
-          <span className="detail-label">Wind Speed</span>
-          <span className="detail-value">{weather.windSpeed.toFixed(1)} km/h</span>
+          <span className="detail-label">{t('ui.windSpeed', 'Wind Speed')}</span>
+          <span className="detail-value">
+            {weather.windSpeed.toLocaleString(document.documentElement.lang || undefined, {
+              minimumFractionDigits: 1,
+              maximumFractionDigits: 1,
+            })}{' '}
+            km/h
+          </span>
         </div>
       </div>
 
       <button className="refresh-btn" onClick={refreshWeather}>
-        🔄 Refresh
+        🔄 {t('ui.refresh', 'Refresh')}
       </button>
     </div>
   );
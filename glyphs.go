@@ -0,0 +1,448 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/vector"
+)
+
+// Condition categories derived from Open-Meteo weather codes. They key the
+// tray icon glyphs and colours, independent of the display language.
+const (
+	ConditionClear        = "clear"
+	ConditionPartlyCloudy = "partlyCloudy"
+	ConditionCloudy       = "cloudy"
+	ConditionFog          = "fog"
+	ConditionDrizzle      = "drizzle"
+	ConditionRain         = "rain"
+	ConditionFreezingRain = "freezingRain"
+	ConditionSnow         = "snow"
+	ConditionRainShowers  = "rainShowers"
+	ConditionSnowShowers  = "snowShowers"
+	ConditionThunderstorm = "thunderstorm"
+	ConditionHail         = "hail"
+	ConditionUnknown      = "unknown"
+)
+
+// glyphDesignSize is the size of the coordinate space glyphs are drawn in
+const glyphDesignSize = 64
+
+// conditionCategory groups an Open-Meteo weather code into a category
+func conditionCategory(code int) string {
+	switch code {
+	case 0:
+		return ConditionClear
+	case 1, 2:
+		return ConditionPartlyCloudy
+	case 3:
+		return ConditionCloudy
+	case 45, 48:
+		return ConditionFog
+	case 51, 53, 55:
+		return ConditionDrizzle
+	case 61, 63, 65:
+		return ConditionRain
+	case 56, 57, 66, 67:
+		return ConditionFreezingRain
+	case 71, 73, 75, 77:
+		return ConditionSnow
+	case 80, 81, 82:
+		return ConditionRainShowers
+	case 85, 86:
+		return ConditionSnowShowers
+	case 95:
+		return ConditionThunderstorm
+	case 96, 99:
+		return ConditionHail
+	default:
+		return ConditionUnknown
+	}
+}
+
+// conditionColor returns the icon background colour for the weather code,
+// with darker variants at night
+func conditionColor(weather *WeatherData) color.RGBA {
+	switch conditionCategory(weather.WeatherCode) {
+	case ConditionClear:
+		if !weather.IsDay {
+			return color.RGBA{40, 53, 147, 255} // Night blue
+		}
+		return color.RGBA{255, 193, 7, 255} // Amber/Yellow
+	case ConditionPartlyCloudy:
+		if !weather.IsDay {
+			return color.RGBA{84, 110, 122, 255} // Blue gray
+		}
+		return color.RGBA{255, 213, 79, 255} // Pale amber
+	case ConditionCloudy:
+		return color.RGBA{158, 158, 158, 255} // Gray
+	case ConditionFog:
+		return color.RGBA{189, 189, 189, 255} // Light gray
+	case ConditionDrizzle, ConditionRain, ConditionRainShowers:
+		return color.RGBA{33, 150, 243, 255} // Blue
+	case ConditionFreezingRain:
+		return color.RGBA{0, 172, 193, 255} // Cyan
+	case ConditionSnow, ConditionSnowShowers:
+		return color.RGBA{224, 247, 250, 255} // Light cyan
+	case ConditionThunderstorm, ConditionHail:
+		return color.RGBA{63, 81, 181, 255} // Dark blue
+	default:
+		return color.RGBA{102, 126, 234, 255} // Default purple
+	}
+}
+
+// Glyph colours
+var (
+	glyphSun       = color.RGBA{255, 193, 7, 255}
+	glyphMoon      = color.RGBA{255, 236, 179, 255}
+	glyphCloud     = color.RGBA{236, 239, 241, 255}
+	glyphDarkCloud = color.RGBA{144, 164, 174, 255}
+	glyphRain      = color.RGBA{33, 150, 243, 255}
+	glyphSnow      = color.RGBA{225, 245, 254, 255}
+	glyphFog       = color.RGBA{176, 190, 197, 255}
+	glyphBolt      = color.RGBA{255, 214, 0, 255}
+)
+
+// glyphPart is one filled layer of a glyph. Parts with knockout clear a thin
+// gap in the layers below them so overlapping shapes stay distinct, which
+// matters for monochrome icons.
+type glyphPart struct {
+	color    color.RGBA
+	shape    func(p *glyphPath)
+	cut      func(p *glyphPath)
+	knockout bool
+}
+
+// glyphParts returns the layers of the glyph for a weather code, from back to
+// front
+func glyphParts(code int, isDay bool) []glyphPart {
+	celestial := func(small bool) glyphPart {
+		if isDay {
+			return sunPart(small)
+		}
+		return moonPart(small)
+	}
+
+	switch conditionCategory(code) {
+	case ConditionClear:
+		return []glyphPart{celestial(false)}
+	case ConditionPartlyCloudy:
+		return []glyphPart{celestial(true), cloudPart(glyphCloud, 4)}
+	case ConditionCloudy:
+		return []glyphPart{cloudPart(glyphDarkCloud, -4), cloudPart(glyphCloud, 4)}
+	case ConditionFog:
+		return []glyphPart{cloudPart(glyphCloud, -8), fogPart()}
+	case ConditionDrizzle:
+		return []glyphPart{cloudPart(glyphDarkCloud, -6), dropsPart(glyphRain, 2.2)}
+	case ConditionRain:
+		return []glyphPart{cloudPart(glyphDarkCloud, -6), rainPart()}
+	case ConditionFreezingRain:
+		return []glyphPart{cloudPart(glyphDarkCloud, -6), rainPart(), flakesPart(true)}
+	case ConditionSnow:
+		return []glyphPart{cloudPart(glyphDarkCloud, -6), flakesPart(false)}
+	case ConditionRainShowers:
+		return []glyphPart{celestial(true), cloudPart(glyphDarkCloud, -2), rainPart()}
+	case ConditionSnowShowers:
+		return []glyphPart{celestial(true), cloudPart(glyphDarkCloud, -2), flakesPart(false)}
+	case ConditionThunderstorm:
+		return []glyphPart{cloudPart(glyphDarkCloud, -6), boltPart()}
+	case ConditionHail:
+		return []glyphPart{cloudPart(glyphDarkCloud, -6), boltPart(), dropsPart(glyphSnow, 2.6)}
+	default:
+		return []glyphPart{cloudPart(glyphCloud, 0)}
+	}
+}
+
+// sunPart is a disc with eight rays, full size or small in the top left
+func sunPart(small bool) glyphPart {
+	cx, cy, r, ray0, ray1, width := float32(32), float32(32), float32(12), float32(17), float32(25), float32(4)
+	if small {
+		cx, cy, r, ray0, ray1, width = 22, 22, 9, 13, 19, 3
+	}
+
+	return glyphPart{
+		color: glyphSun,
+		shape: func(p *glyphPath) {
+			p.circle(cx, cy, r)
+			for i := 0; i < 8; i++ {
+				angle := float64(i) * math.Pi / 4
+				dx, dy := float32(math.Cos(angle)), float32(math.Sin(angle))
+				p.line(cx+dx*ray0, cy+dy*ray0, cx+dx*ray1, cy+dy*ray1, width)
+			}
+		},
+	}
+}
+
+// moonPart is a crescent, full size or small in the top left
+func moonPart(small bool) glyphPart {
+	cx, cy, r, offset := float32(32), float32(32), float32(17), float32(9)
+	if small {
+		cx, cy, r, offset = 22, 22, 13, 7
+	}
+
+	return glyphPart{
+		color: glyphMoon,
+		shape: func(p *glyphPath) {
+			p.circle(cx, cy, r)
+		},
+		cut: func(p *glyphPath) {
+			p.circle(cx+offset, cy-offset, r*0.8)
+		},
+	}
+}
+
+// cloudPart is a cloud shifted vertically by dy
+func cloudPart(c color.RGBA, dy float32) glyphPart {
+	return glyphPart{
+		color:    c,
+		knockout: true,
+		shape: func(p *glyphPath) {
+			p.circle(22, 36+dy, 11)
+			p.circle(35, 28+dy, 14)
+			p.circle(46, 37+dy, 10)
+			p.rect(22, 36+dy, 46, 47+dy)
+		},
+	}
+}
+
+// rainPart is three slanted rain streaks below a raised cloud
+func rainPart() glyphPart {
+	return glyphPart{
+		color: glyphRain,
+		shape: func(p *glyphPath) {
+			for _, x := range []float32{23, 33, 43} {
+				p.line(x, 47, x-4, 58, 3.5)
+			}
+		},
+	}
+}
+
+// dropsPart is three round drops or hail stones below a raised cloud
+func dropsPart(c color.RGBA, r float32) glyphPart {
+	return glyphPart{
+		color: c,
+		shape: func(p *glyphPath) {
+			p.circle(22, 51, r)
+			p.circle(32, 56, r)
+			p.circle(42, 51, r)
+		},
+	}
+}
+
+// flakesPart is three snowflakes, or one between rain streaks when mixed
+func flakesPart(mixed bool) glyphPart {
+	centers := [][2]float32{{20, 54}, {32, 57}, {44, 54}}
+	if mixed {
+		centers = [][2]float32{{28, 58}}
+	}
+
+	return glyphPart{
+		color: glyphSnow,
+		shape: func(p *glyphPath) {
+			for _, center := range centers {
+				for i := 0; i < 3; i++ {
+					angle := float64(i)*math.Pi/3 + math.Pi/2
+					dx, dy := float32(math.Cos(angle))*4.5, float32(math.Sin(angle))*4.5
+					p.line(center[0]-dx, center[1]-dy, center[0]+dx, center[1]+dy, 2)
+				}
+			}
+		},
+	}
+}
+
+// fogPart is two horizontal bars below a raised cloud
+func fogPart() glyphPart {
+	return glyphPart{
+		color: glyphFog,
+		shape: func(p *glyphPath) {
+			p.line(12, 46, 52, 46, 4)
+			p.line(16, 55, 48, 55, 4)
+		},
+	}
+}
+
+// boltPart is a lightning bolt below a raised cloud
+func boltPart() glyphPart {
+	return glyphPart{
+		color:    glyphBolt,
+		knockout: true,
+		shape: func(p *glyphPath) {
+			p.polygon([][2]float32{{35, 42}, {25, 54}, {31, 54}, {27, 63}, {41, 49}, {34, 49}, {39, 42}})
+		},
+	}
+}
+
+// glyphPath draws shapes given in glyph design units into a rasterizer
+// covering a target rectangle
+type glyphPath struct {
+	z     *vector.Rasterizer
+	scale float32
+	grow  float32
+}
+
+// newGlyphPath creates a path for a size x size pixel target. Shapes are
+// grown by grow design units, which is used to build knockout gaps.
+func newGlyphPath(size int, grow float32) *glyphPath {
+	return &glyphPath{
+		z:     vector.NewRasterizer(size, size),
+		scale: float32(size) / glyphDesignSize,
+		grow:  grow,
+	}
+}
+
+// circle adds a circle built from four cubic Béziers
+func (p *glyphPath) circle(cx, cy, r float32) {
+	r += p.grow
+	const k = 0.5522847 // Control point distance for a quarter circle
+	s := p.scale
+	cx, cy, r = cx*s, cy*s, r*s
+
+	p.z.MoveTo(cx+r, cy)
+	p.z.CubeTo(cx+r, cy+k*r, cx+k*r, cy+r, cx, cy+r)
+	p.z.CubeTo(cx-k*r, cy+r, cx-r, cy+k*r, cx-r, cy)
+	p.z.CubeTo(cx-r, cy-k*r, cx-k*r, cy-r, cx, cy-r)
+	p.z.CubeTo(cx+k*r, cy-r, cx+r, cy-k*r, cx+r, cy)
+	p.z.ClosePath()
+}
+
+// rect adds an axis-aligned rectangle
+func (p *glyphPath) rect(x0, y0, x1, y1 float32) {
+	g := p.grow
+	p.polygon([][2]float32{{x0 - g, y0 - g}, {x1 + g, y0 - g}, {x1 + g, y1 + g}, {x0 - g, y1 + g}})
+}
+
+// line adds a stroke with round caps
+func (p *glyphPath) line(x0, y0, x1, y1, width float32) {
+	dx, dy := x1-x0, y1-y0
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		p.circle(x0, y0, width/2)
+		return
+	}
+
+	half := width/2 + p.grow
+	nx, ny := -dy/length*half, dx/length*half
+	p.polygonExact([][2]float32{{x0 + nx, y0 + ny}, {x1 + nx, y1 + ny}, {x1 - nx, y1 - ny}, {x0 - nx, y0 - ny}})
+	p.circle(x0, y0, width/2)
+	p.circle(x1, y1, width/2)
+}
+
+// polygon adds a closed polygon, grown outwards from its centroid
+func (p *glyphPath) polygon(points [][2]float32) {
+	if p.grow == 0 {
+		p.polygonExact(points)
+		return
+	}
+
+	var cx, cy float32
+	for _, point := range points {
+		cx += point[0]
+		cy += point[1]
+	}
+	cx /= float32(len(points))
+	cy /= float32(len(points))
+
+	grown := make([][2]float32, len(points))
+	for i, point := range points {
+		dx, dy := point[0]-cx, point[1]-cy
+		d := float32(math.Hypot(float64(dx), float64(dy)))
+		if d == 0 {
+			grown[i] = point
+			continue
+		}
+		grown[i] = [2]float32{point[0] + dx/d*p.grow, point[1] + dy/d*p.grow}
+	}
+	p.polygonExact(grown)
+}
+
+// polygonExact adds a closed polygon. Points are reordered to a consistent
+// winding so overlapping shapes add up instead of cancelling out.
+func (p *glyphPath) polygonExact(points [][2]float32) {
+	var area float32
+	for i := range points {
+		j := (i + 1) % len(points)
+		area += points[i][0]*points[j][1] - points[j][0]*points[i][1]
+	}
+
+	ordered := points
+	if area < 0 {
+		ordered = make([][2]float32, len(points))
+		for i, point := range points {
+			ordered[len(points)-1-i] = point
+		}
+	}
+
+	s := p.scale
+	p.z.MoveTo(ordered[0][0]*s, ordered[0][1]*s)
+	for _, point := range ordered[1:] {
+		p.z.LineTo(point[0]*s, point[1]*s)
+	}
+	p.z.ClosePath()
+}
+
+// mask rasterizes the path into an alpha mask
+func (p *glyphPath) mask() *image.Alpha {
+	size := p.z.Size()
+	mask := image.NewAlpha(image.Rect(0, 0, size.X, size.Y))
+	p.z.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+	return mask
+}
+
+// partMask rasterizes a glyph part at the given size, minus its cut shape
+func partMask(part glyphPart, size int, grow float32) *image.Alpha {
+	path := newGlyphPath(size, grow)
+	part.shape(path)
+	mask := path.mask()
+
+	if part.cut != nil {
+		cutPath := newGlyphPath(size, -grow)
+		part.cut(cutPath)
+		cut := cutPath.mask()
+		for i := range mask.Pix {
+			mask.Pix[i] = uint8(uint32(mask.Pix[i]) * (255 - uint32(cut.Pix[i])) / 255)
+		}
+	}
+
+	return mask
+}
+
+// drawConditionGlyph draws the glyph for the weather code and day/night flag
+// into rect. When mono is set every part is drawn in that colour.
+func drawConditionGlyph(img *image.RGBA, rect image.Rectangle, weather *WeatherData, mono color.Color) {
+	size := min(rect.Dx(), rect.Dy())
+	if size <= 0 {
+		return
+	}
+	origin := rect.Min
+
+	for _, part := range glyphParts(weather.WeatherCode, weather.IsDay) {
+		if part.knockout {
+			gap := partMask(part, size, 2.5)
+			for y := 0; y < size; y++ {
+				for x := 0; x < size; x++ {
+					a := uint32(gap.AlphaAt(x, y).A)
+					if a == 0 {
+						continue
+					}
+					offset := img.PixOffset(origin.X+x, origin.Y+y)
+					if offset < 0 || offset+4 > len(img.Pix) {
+						continue
+					}
+					for c := 0; c < 4; c++ {
+						img.Pix[offset+c] = uint8(uint32(img.Pix[offset+c]) * (255 - a) / 255)
+					}
+				}
+			}
+		}
+
+		var fill color.Color = part.color
+		if mono != nil {
+			fill = mono
+		}
+		mask := partMask(part, size, 0)
+		draw.DrawMask(img, image.Rectangle{Min: origin, Max: origin.Add(image.Pt(size, size))},
+			image.NewUniform(fill), image.Point{}, mask, image.Point{}, draw.Over)
+	}
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultLanguage is used for unknown languages and missing messages
+const defaultLanguage = "en"
+
+// messages is the message catalogue, keyed by language then message ID.
+// Every language must define every key present in the default language.
+var messages = map[string]map[string]string{
+	"en": {
+		"condition.clearSky":         "Clear Sky",
+		"condition.partlyCloudy":     "Partly Cloudy",
+		"condition.foggy":            "Foggy",
+		"condition.drizzle":          "Drizzle",
+		"condition.rainy":            "Rainy",
+		"condition.freezingRain":     "Freezing Rain",
+		"condition.snowy":            "Snowy",
+		"condition.snowGrains":       "Snow Grains",
+		"condition.rainShowers":      "Rain Showers",
+		"condition.snowShowers":      "Snow Showers",
+		"condition.thunderstorm":     "Thunderstorm",
+		"condition.thunderstormHail": "Thunderstorm with Hail",
+		"condition.unknown":          "Unknown",
+		"weather.description":        "%s in %s",
+		"weekday.0":                  "Sunday",
+		"weekday.1":                  "Monday",
+		"weekday.2":                  "Tuesday",
+		"weekday.3":                  "Wednesday",
+		"weekday.4":                  "Thursday",
+		"weekday.5":                  "Friday",
+		"weekday.6":                  "Saturday",
+		"ui.showWeather":             "Show Weather",
+		"ui.refreshWeather":          "Refresh Weather",
+		"ui.profiles":                "Profiles",
+		"ui.noProfiles":              "No saved profiles",
+		"ui.about":                   "About myWeatherApp",
+		"ui.quit":                    "Quit",
+		"ui.loading":                 "Loading weather...",
+		"ui.loadError":               "Unable to load weather data",
+		"ui.updated":                 "Updated",
+		"ui.feelsLike":               "Feels like",
+		"ui.humidity":                "Humidity",
+		"ui.windSpeed":               "Wind Speed",
+		"ui.refresh":                 "Refresh",
+		"ui.hideToTray":              "Hide to tray",
+		"alert.thunderstorm":         "Thunderstorm warning",
+		"alert.freezingRain":         "Freezing rain warning",
+		"alert.extremeCold":          "Extreme cold warning",
+		"alert.extremeHeat":          "Extreme heat warning",
+		"ui.acknowledgeAlert":        "Acknowledge Alert",
+		"ui.forecast":                "Forecast",
+		"ui.lastUpdated":             "Last updated: %s",
+		"time.justNow":               "just now",
+		"time.minutesAgo":            "%d min ago",
+		"time.hoursAgo":              "%d h ago",
+		"time.daysAgo":               "%d days ago",
+		"ui.tooltipTemplate":         "{location}: {temp}{unit}, {condition}\nHigh {high}{unit} / Low {low}{unit}\nRain next hour: {rain}%\nUpdated {updated}",
+	},
+	"de": {
+		"condition.clearSky":         "Klarer Himmel",
+		"condition.partlyCloudy":     "Teilweise bewölkt",
+		"condition.foggy":            "Nebel",
+		"condition.drizzle":          "Nieselregen",
+		"condition.rainy":            "Regen",
+		"condition.freezingRain":     "Gefrierender Regen",
+		"condition.snowy":            "Schnee",
+		"condition.snowGrains":       "Schneegriesel",
+		"condition.rainShowers":      "Regenschauer",
+		"condition.snowShowers":      "Schneeschauer",
+		"condition.thunderstorm":     "Gewitter",
+		"condition.thunderstormHail": "Gewitter mit Hagel",
+		"condition.unknown":          "Unbekannt",
+		"weather.description":        "%s in %s",
+		"weekday.0":                  "Sonntag",
+		"weekday.1":                  "Montag",
+		"weekday.2":                  "Dienstag",
+		"weekday.3":                  "Mittwoch",
+		"weekday.4":                  "Donnerstag",
+		"weekday.5":                  "Freitag",
+		"weekday.6":                  "Samstag",
+		"ui.showWeather":             "Wetter anzeigen",
+		"ui.refreshWeather":          "Wetter aktualisieren",
+		"ui.profiles":                "Profile",
+		"ui.noProfiles":              "Keine gespeicherten Profile",
+		"ui.about":                   "Über myWeatherApp",
+		"ui.quit":                    "Beenden",
+		"ui.loading":                 "Wetter wird geladen...",
+		"ui.loadError":               "Wetterdaten konnten nicht geladen werden",
+		"ui.updated":                 "Aktualisiert",
+		"ui.feelsLike":               "Gefühlt",
+		"ui.humidity":                "Luftfeuchtigkeit",
+		"ui.windSpeed":               "Windgeschwindigkeit",
+		"ui.refresh":                 "Aktualisieren",
+		"ui.hideToTray":              "Im Infobereich ausblenden",
+		"alert.thunderstorm":         "Gewitterwarnung",
+		"alert.freezingRain":         "Warnung vor gefrierendem Regen",
+		"alert.extremeCold":          "Warnung vor extremer Kälte",
+		"alert.extremeHeat":          "Hitzewarnung",
+		"ui.acknowledgeAlert":        "Warnung bestätigen",
+		"ui.forecast":                "Vorhersage",
+		"ui.lastUpdated":             "Zuletzt aktualisiert: %s",
+		"time.justNow":               "gerade eben",
+		"time.minutesAgo":            "vor %d Min.",
+		"time.hoursAgo":              "vor %d Std.",
+		"time.daysAgo":               "vor %d Tagen",
+		"ui.tooltipTemplate":         "{location}: {temp}{unit}, {condition}\nHöchst {high}{unit} / Tiefst {low}{unit}\nRegen nächste Stunde: {rain}%\nAktualisiert {updated}",
+	},
+	"fr": {
+		"condition.clearSky":         "Ciel dégagé",
+		"condition.partlyCloudy":     "Partiellement nuageux",
+		"condition.foggy":            "Brouillard",
+		"condition.drizzle":          "Bruine",
+		"condition.rainy":            "Pluie",
+		"condition.freezingRain":     "Pluie verglaçante",
+		"condition.snowy":            "Neige",
+		"condition.snowGrains":       "Neige en grains",
+		"condition.rainShowers":      "Averses de pluie",
+		"condition.snowShowers":      "Averses de neige",
+		"condition.thunderstorm":     "Orage",
+		"condition.thunderstormHail": "Orage avec grêle",
+		"condition.unknown":          "Inconnu",
+		"weather.description":        "%s à %s",
+		"weekday.0":                  "dimanche",
+		"weekday.1":                  "lundi",
+		"weekday.2":                  "mardi",
+		"weekday.3":                  "mercredi",
+		"weekday.4":                  "jeudi",
+		"weekday.5":                  "vendredi",
+		"weekday.6":                  "samedi",
+		"ui.showWeather":             "Afficher la météo",
+		"ui.refreshWeather":          "Actualiser la météo",
+		"ui.profiles":                "Profils",
+		"ui.noProfiles":              "Aucun profil enregistré",
+		"ui.about":                   "À propos de myWeatherApp",
+		"ui.quit":                    "Quitter",
+		"ui.loading":                 "Chargement de la météo...",
+		"ui.loadError":               "Impossible de charger la météo",
+		"ui.updated":                 "Mis à jour",
+		"ui.feelsLike":               "Ressenti",
+		"ui.humidity":                "Humidité",
+		"ui.windSpeed":               "Vitesse du vent",
+		"ui.refresh":                 "Actualiser",
+		"ui.hideToTray":              "Masquer dans la zone de notification",
+		"alert.thunderstorm":         "Alerte orages",
+		"alert.freezingRain":         "Alerte pluie verglaçante",
+		"alert.extremeCold":          "Alerte grand froid",
+		"alert.extremeHeat":          "Alerte canicule",
+		"ui.acknowledgeAlert":        "Confirmer l'alerte",
+		"ui.forecast":                "Prévisions",
+		"ui.lastUpdated":             "Dernière mise à jour : %s",
+		"time.justNow":               "à l'instant",
+		"time.minutesAgo":            "il y a %d min",
+		"time.hoursAgo":              "il y a %d h",
+		"time.daysAgo":               "il y a %d jours",
+		"ui.tooltipTemplate":         "{location} : {temp}{unit}, {condition}\nMax {high}{unit} / Min {low}{unit}\nPluie dans l'heure : {rain} %\nMis à jour {updated}",
+	},
+	"es": {
+		"condition.clearSky":         "Cielo despejado",
+		"condition.partlyCloudy":     "Parcialmente nublado",
+		"condition.foggy":            "Niebla",
+		"condition.drizzle":          "Llovizna",
+		"condition.rainy":            "Lluvia",
+		"condition.freezingRain":     "Lluvia helada",
+		"condition.snowy":            "Nieve",
+		"condition.snowGrains":       "Cinarra",
+		"condition.rainShowers":      "Chubascos",
+		"condition.snowShowers":      "Chubascos de nieve",
+		"condition.thunderstorm":     "Tormenta",
+		"condition.thunderstormHail": "Tormenta con granizo",
+		"condition.unknown":          "Desconocido",
+		"weather.description":        "%s en %s",
+		"weekday.0":                  "domingo",
+		"weekday.1":                  "lunes",
+		"weekday.2":                  "martes",
+		"weekday.3":                  "miércoles",
+		"weekday.4":                  "jueves",
+		"weekday.5":                  "viernes",
+		"weekday.6":                  "sábado",
+		"ui.showWeather":             "Mostrar el tiempo",
+		"ui.refreshWeather":          "Actualizar el tiempo",
+		"ui.profiles":                "Perfiles",
+		"ui.noProfiles":              "No hay perfiles guardados",
+		"ui.about":                   "Acerca de myWeatherApp",
+		"ui.quit":                    "Salir",
+		"ui.loading":                 "Cargando el tiempo...",
+		"ui.loadError":               "No se pudo cargar el tiempo",
+		"ui.updated":                 "Actualizado",
+		"ui.feelsLike":               "Sensación",
+		"ui.humidity":                "Humedad",
+		"ui.windSpeed":               "Velocidad del viento",
+		"ui.refresh":                 "Actualizar",
+		"ui.hideToTray":              "Ocultar en la bandeja",
+		"alert.thunderstorm":         "Aviso de tormenta",
+		"alert.freezingRain":         "Aviso de lluvia helada",
+		"alert.extremeCold":          "Aviso de frío extremo",
+		"alert.extremeHeat":          "Aviso de calor extremo",
+		"ui.acknowledgeAlert":        "Confirmar aviso",
+		"ui.forecast":                "Pronóstico",
+		"ui.lastUpdated":             "Última actualización: %s",
+		"time.justNow":               "ahora mismo",
+		"time.minutesAgo":            "hace %d min",
+		"time.hoursAgo":              "hace %d h",
+		"time.daysAgo":               "hace %d días",
+		"ui.tooltipTemplate":         "{location}: {temp}{unit}, {condition}\nMáx. {high}{unit} / Mín. {low}{unit}\nLluvia próxima hora: {rain}%\nActualizado {updated}",
+	},
+	"sv": {
+		"condition.clearSky":         "Klar himmel",
+		"condition.partlyCloudy":     "Delvis molnigt",
+		"condition.foggy":            "Dimma",
+		"condition.drizzle":          "Duggregn",
+		"condition.rainy":            "Regn",
+		"condition.freezingRain":     "Underkylt regn",
+		"condition.snowy":            "Snö",
+		"condition.snowGrains":       "Kornsnö",
+		"condition.rainShowers":      "Regnskurar",
+		"condition.snowShowers":      "Snöbyar",
+		"condition.thunderstorm":     "Åska",
+		"condition.thunderstormHail": "Åska med hagel",
+		"condition.unknown":          "Okänt",
+		"weather.description":        "%s i %s",
+		"weekday.0":                  "söndag",
+		"weekday.1":                  "måndag",
+		"weekday.2":                  "tisdag",
+		"weekday.3":                  "onsdag",
+		"weekday.4":                  "torsdag",
+		"weekday.5":                  "fredag",
+		"weekday.6":                  "lördag",
+		"ui.showWeather":             "Visa väder",
+		"ui.refreshWeather":          "Uppdatera väder",
+		"ui.profiles":                "Profiler",
+		"ui.noProfiles":              "Inga sparade profiler",
+		"ui.about":                   "Om myWeatherApp",
+		"ui.quit":                    "Avsluta",
+		"ui.loading":                 "Laddar väder...",
+		"ui.loadError":               "Kunde inte ladda väderdata",
+		"ui.updated":                 "Uppdaterad",
+		"ui.feelsLike":               "Känns som",
+		"ui.humidity":                "Luftfuktighet",
+		"ui.windSpeed":               "Vindhastighet",
+		"ui.refresh":                 "Uppdatera",
+		"ui.hideToTray":              "Göm i systemfältet",
+		"alert.thunderstorm":         "Varning för åska",
+		"alert.freezingRain":         "Varning för underkylt regn",
+		"alert.extremeCold":          "Varning för sträng kyla",
+		"alert.extremeHeat":          "Varning för höga temperaturer",
+		"ui.acknowledgeAlert":        "Bekräfta varning",
+		"ui.forecast":                "Prognos",
+		"ui.lastUpdated":             "Senast uppdaterad: %s",
+		"time.justNow":               "just nu",
+		"time.minutesAgo":            "för %d min sedan",
+		"time.hoursAgo":              "för %d tim sedan",
+		"time.daysAgo":               "för %d dagar sedan",
+		"ui.tooltipTemplate":         "{location}: {temp}{unit}, {condition}\nHögst {high}{unit} / Lägst {low}{unit}\nRegn nästa timme: {rain} %\nUppdaterad {updated}",
+	},
+}
+
+// decimalSeparators lists languages that do not use a decimal point
+var decimalSeparators = map[string]string{
+	"de": ",",
+	"fr": ",",
+	"es": ",",
+	"sv": ",",
+}
+
+// normalizeLanguage reduces a language tag such as "de-AT" to a supported
+// catalogue language, falling back to the default language
+func normalizeLanguage(lang string) string {
+	lang = strings.ToLower(lang)
+	if base, _, found := strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-"); found {
+		lang = base
+	}
+
+	if _, ok := messages[lang]; ok {
+		return lang
+	}
+	return defaultLanguage
+}
+
+// translate returns the message for key in lang, formatted with args. Missing
+// messages fall back to the default language, then to the key itself.
+func translate(lang, key string, args ...interface{}) string {
+	message, ok := messages[normalizeLanguage(lang)][key]
+	if !ok {
+		message, ok = messages[defaultLanguage][key]
+	}
+	if !ok {
+		message = key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// weekdayName returns the localized name of a weekday
+func weekdayName(day time.Weekday, lang string) string {
+	return translate(lang, fmt.Sprintf("weekday.%d", int(day)))
+}
+
+// formatNumber formats a number with the given decimals and the decimal
+// separator of the language
+func formatNumber(value float64, decimals int, lang string) string {
+	formatted := fmt.Sprintf("%.*f", decimals, value)
+	if rounded, _ := strconv.ParseFloat(formatted, 64); rounded == 0 {
+		formatted = strings.TrimPrefix(formatted, "-") // no "-0"
+	}
+
+	if separator, ok := decimalSeparators[normalizeLanguage(lang)]; ok {
+		formatted = strings.Replace(formatted, ".", separator, 1)
+	}
+	return formatted
+}
+
+// currentLanguage returns the configured language
+func (a *App) currentLanguage() string {
+	config, err := a.LoadConfig()
+	if err != nil {
+		return defaultLanguage
+	}
+	return config.Language
+}
+
+// GetMessages returns the message catalogue for the configured language so
+// the frontend can translate its labels
+func (a *App) GetMessages() (map[string]string, error) {
+	config, err := a.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	lang := normalizeLanguage(config.Language)
+	catalogue := make(map[string]string, len(messages[defaultLanguage]))
+	for key := range messages[defaultLanguage] {
+		catalogue[key] = translate(lang, key)
+	}
+
+	return catalogue, nil
+}
+
+// GetSupportedLanguages returns the languages with a message catalogue
+func (a *App) GetSupportedLanguages() []string {
+	return []string{"de", "en", "es", "fr", "sv"}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// messagePlaceholderPattern matches printf verbs and {name} template fields
+var messagePlaceholderPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z]|\{[A-Za-z]+\}`)
+
+// messagePlaceholders returns the printf verbs of a message in order,
+// followed by its template fields in sorted order
+func messagePlaceholders(message string) []string {
+	var verbs, fields []string
+	for _, match := range messagePlaceholderPattern.FindAllString(message, -1) {
+		if match[0] == '%' {
+			verbs = append(verbs, match)
+		} else {
+			fields = append(fields, match)
+		}
+	}
+	slices.Sort(fields)
+	return append(verbs, fields...)
+}
+
+func TestMessageCataloguesComplete(t *testing.T) {
+	base := messages[defaultLanguage]
+
+	for _, lang := range (&App{}).GetSupportedLanguages() {
+		catalogue, ok := messages[lang]
+		if !ok {
+			t.Errorf("%s has no message catalogue", lang)
+			continue
+		}
+
+		for key, message := range base {
+			translated, ok := catalogue[key]
+			if !ok {
+				t.Errorf("%s is missing %s", lang, key)
+				continue
+			}
+			want := messagePlaceholders(message)
+			if got := messagePlaceholders(translated); !slices.Equal(got, want) {
+				t.Errorf("%s %s has placeholders %v, want %v", lang, key, got, want)
+			}
+		}
+		for key := range catalogue {
+			if _, ok := base[key]; !ok {
+				t.Errorf("%s defines %s, which %s does not", lang, key, defaultLanguage)
+			}
+		}
+	}
+}
+
+func TestGetMessagesUsesConfiguredLanguage(t *testing.T) {
+	setTestHome(t)
+	t.Setenv("MYWEATHERAPP_LANGUAGE", "de")
+
+	catalogue, err := (&App{}).GetMessages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(catalogue) != len(messages[defaultLanguage]) {
+		t.Errorf("GetMessages returned %d messages, want %d", len(catalogue), len(messages[defaultLanguage]))
+	}
+	if got := catalogue["ui.refresh"]; got != messages["de"]["ui.refresh"] {
+		t.Errorf("ui.refresh = %q, want the German message", got)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// macKeychainStore stores secrets in the login keychain via the security tool
+type macKeychainStore struct{}
+
+// newKeyringSecretStore returns the macOS keychain store
+func newKeyringSecretStore() SecretStore {
+	if _, err := exec.LookPath("security"); err != nil {
+		return nil
+	}
+	return macKeychainStore{}
+}
+
+func (macKeychainStore) Name() string {
+	return SecretsBackendKeyring
+}
+
+func (macKeychainStore) Get(name string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password",
+		"-s", secretsService, "-a", name, "-w").Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 44 {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("keychain lookup failed: %w", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (macKeychainStore) Set(name, value string) error {
+	// The command is read from stdin in interactive mode so the value never
+	// appears in the process list. It is hex encoded with -X, which needs
+	// no quoting. Names are restricted by secretNamePattern.
+	var stderr bytes.Buffer
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		secretsService, name, hex.EncodeToString([]byte(value))))
+	cmd.Stderr = &stderr
+	// Interactive mode reports failed commands on stderr
+	if err := cmd.Run(); err != nil || stderr.Len() > 0 {
+		return fmt.Errorf("keychain update failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (macKeychainStore) Delete(name string) error {
+	err := exec.Command("security", "delete-generic-password",
+		"-s", secretsService, "-a", name).Run()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 44 {
+			return ErrSecretNotFound
+		}
+		return fmt.Errorf("keychain delete failed: %w", err)
+	}
+	return nil
+}
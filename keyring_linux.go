@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// secretServiceStore stores secrets through the freedesktop Secret Service
+// (GNOME Keyring, KWallet) using the secret-tool command from libsecret
+type secretServiceStore struct{}
+
+// newKeyringSecretStore returns the Secret Service store when secret-tool is
+// installed
+func newKeyringSecretStore() SecretStore {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil
+	}
+	return secretServiceStore{}
+}
+
+func (secretServiceStore) Name() string {
+	return SecretsBackendKeyring
+}
+
+func (secretServiceStore) Get(name string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup",
+		"service", secretsService, "account", name).Output()
+	if err != nil {
+		// secret-tool exits with status 1 and no output for missing items
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 && len(out) == 0 {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("secret service lookup failed: %w", err)
+	}
+	return string(out), nil
+}
+
+func (secretServiceStore) Set(name, value string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "store", "--label", secretsService+": "+name,
+		"service", secretsService, "account", name)
+	// The value is passed on stdin so it never appears in the process list
+	cmd.Stdin = strings.NewReader(value)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("secret service update failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (s secretServiceStore) Delete(name string) error {
+	// secret-tool clear succeeds whether or not the item exists
+	if _, err := s.Get(name); err != nil {
+		return err
+	}
+
+	err := exec.Command("secret-tool", "clear",
+		"service", secretsService, "account", name).Run()
+	if err != nil {
+		return fmt.Errorf("secret service delete failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !darwin && !linux && !windows
+
+package main
+
+// newKeyringSecretStore reports that no OS keyring is supported on this
+// platform, so the encrypted file store is used
+func newKeyringSecretStore() SecretStore {
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Windows Credential Manager API
+var (
+	advapi32       = windows.NewLazySystemDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+)
+
+// credential mirrors the Win32 CREDENTIALW structure
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        windows.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// credentialManagerStore stores secrets as generic Windows credentials
+type credentialManagerStore struct{}
+
+// newKeyringSecretStore returns the Windows Credential Manager store
+func newKeyringSecretStore() SecretStore {
+	if advapi32.Load() != nil {
+		return nil
+	}
+	return credentialManagerStore{}
+}
+
+// credentialTarget returns the credential target name for a secret
+func credentialTarget(name string) (*uint16, error) {
+	return windows.UTF16PtrFromString(secretsService + ":" + name)
+}
+
+func (credentialManagerStore) Name() string {
+	return SecretsBackendKeyring
+}
+
+func (credentialManagerStore) Get(name string) (string, error) {
+	target, err := credentialTarget(name)
+	if err != nil {
+		return "", err
+	}
+
+	var cred *credential
+	ret, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0,
+		uintptr(unsafe.Pointer(&cred)))
+	if ret == 0 {
+		if err == windows.ERROR_NOT_FOUND {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("credential read failed: %w", err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+
+	blob := unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)
+	return string(blob), nil
+}
+
+func (credentialManagerStore) Set(name, value string) error {
+	target, err := credentialTarget(name)
+	if err != nil {
+		return err
+	}
+
+	blob := []byte(value)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+
+	ret, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0)
+	if ret == 0 {
+		return fmt.Errorf("credential write failed: %w", err)
+	}
+	return nil
+}
+
+func (credentialManagerStore) Delete(name string) error {
+	target, err := credentialTarget(name)
+	if err != nil {
+		return err
+	}
+
+	ret, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0)
+	if ret == 0 {
+		if err == windows.ERROR_NOT_FOUND {
+			return ErrSecretNotFound
+		}
+		return fmt.Errorf("credential delete failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultTrayLabel matches the label shown before templates were added
+const defaultTrayLabel = "{location}: {temp}{unit} - {condition}"
+
+// labelPlaceholderPattern matches {name} and {name:spec} placeholders in a
+// label template
+var labelPlaceholderPattern = regexp.MustCompile(`\{([A-Za-z]*)(?::([^{}]*))?\}`)
+
+// labelNumberSpecPattern matches the sign and decimals part of a format
+// spec, e.g. "+", ".1" or "+.1"
+var labelNumberSpecPattern = regexp.MustCompile(`^(\+?)(?:\.([0-9]))?$`)
+
+// maxLabelDecimals is the most decimal places a format spec can ask for
+const maxLabelDecimals = 3
+
+// labelPlaceholder formats one placeholder. Numeric placeholders set value
+// and unit and take a format spec; the others set text.
+type labelPlaceholder struct {
+	value func(weather *WeatherData, config *AppConfig) float64
+	unit  func(config *AppConfig) string
+	text  func(weather *WeatherData, config *AppConfig) string
+}
+
+// labelFormat is a parsed placeholder format spec
+type labelFormat struct {
+	decimals int
+	sign     bool
+	unit     bool
+}
+
+// temperaturePlaceholder formats a Celsius temperature of the weather in
+// the configured unit
+func temperaturePlaceholder(celsius func(weather *WeatherData) float64) labelPlaceholder {
+	return labelPlaceholder{
+		value: func(weather *WeatherData, config *AppConfig) float64 {
+			return convertTemperature(celsius(weather), temperatureUnit(config))
+		},
+		unit: func(config *AppConfig) string {
+			return temperatureSymbol(temperatureUnit(config))
+		},
+	}
+}
+
+// percentUnit is the unit of humidity and the chance of rain
+func percentUnit(config *AppConfig) string {
+	return "%"
+}
+
+// labelPlaceholders formats each placeholder for the weather in the
+// configured unit and language
+var labelPlaceholders = map[string]labelPlaceholder{
+	"location": {text: func(weather *WeatherData, config *AppConfig) string {
+		return weather.Location
+	}},
+	"temp":      temperaturePlaceholder(func(weather *WeatherData) float64 { return weather.Temperature }),
+	"feelsLike": temperaturePlaceholder(func(weather *WeatherData) float64 { return weather.FeelsLike }),
+	"high":      temperaturePlaceholder(func(weather *WeatherData) float64 { return weather.TodayMax }),
+	"low":       temperaturePlaceholder(func(weather *WeatherData) float64 { return weather.TodayMin }),
+	"unit": {text: func(weather *WeatherData, config *AppConfig) string {
+		return temperatureSymbol(temperatureUnit(config))
+	}},
+	"humidity": {
+		value: func(weather *WeatherData, config *AppConfig) float64 {
+			return float64(weather.Humidity)
+		},
+		unit: percentUnit,
+	},
+	"wind": {
+		value: func(weather *WeatherData, config *AppConfig) float64 {
+			return weather.WindSpeed
+		},
+		unit: func(config *AppConfig) string {
+			return " km/h"
+		},
+	},
+	"rain": {
+		value: func(weather *WeatherData, config *AppConfig) float64 {
+			return float64(weather.RainChance)
+		},
+		unit: percentUnit,
+	},
+	"condition": {text: func(weather *WeatherData, config *AppConfig) string {
+		return weather.Condition
+	}},
+	"description": {text: func(weather *WeatherData, config *AppConfig) string {
+		return weather.Description
+	}},
+	"updated": {text: func(weather *WeatherData, config *AppConfig) string {
+		return weather.LastUpdated
+	}},
+}
+
+// parseLabelFormat parses a comma-separated format spec such as "+.1,unit".
+// An empty spec formats whole numbers without a unit.
+func parseLabelFormat(spec string) (labelFormat, error) {
+	var format labelFormat
+	if spec == "" {
+		return format, nil
+	}
+
+	for _, option := range strings.Split(spec, ",") {
+		switch option {
+		case "unit":
+			format.unit = true
+		case "nounit":
+			format.unit = false
+		default:
+			match := labelNumberSpecPattern.FindStringSubmatch(option)
+			if match == nil || option == "" {
+				return format, fmt.Errorf("invalid format %q", option)
+			}
+			format.sign = match[1] == "+"
+			if match[2] != "" {
+				format.decimals, _ = strconv.Atoi(match[2])
+				if format.decimals > maxLabelDecimals {
+					return format, fmt.Errorf("at most %d decimal places are allowed, got %q", maxLabelDecimals, option)
+				}
+			}
+		}
+	}
+
+	return format, nil
+}
+
+// formatLabelNumber formats a value with the decimals and sign of the
+// format, rounding halves away from zero like the tray icon
+func formatLabelNumber(value float64, format labelFormat, lang string) string {
+	scale := math.Pow(10, float64(format.decimals))
+	rounded := math.Round(value*scale) / scale
+
+	formatted := formatNumber(rounded, format.decimals, lang)
+	if format.sign && rounded > 0 {
+		formatted = "+" + formatted
+	}
+	return formatted
+}
+
+// formatTemperature rounds a Celsius temperature in the configured unit the
+// same way as the tray icon, halves away from zero
+func formatTemperature(celsius float64, config *AppConfig) string {
+	return formatLabelNumber(convertTemperature(celsius, temperatureUnit(config)), labelFormat{}, config.Language)
+}
+
+// trayLabelTemplate returns the configured template or the default
+func trayLabelTemplate(config *AppConfig) string {
+	if config.TrayLabel == "" {
+		return defaultTrayLabel
+	}
+	return config.TrayLabel
+}
+
+// validateLabelTemplate checks that every placeholder in the named template
+// setting is known, that format specs are valid and only given to numeric
+// placeholders, and that braces are balanced
+func validateLabelTemplate(name, template string) error {
+	for _, match := range labelPlaceholderPattern.FindAllStringSubmatch(template, -1) {
+		placeholder, ok := labelPlaceholders[match[1]]
+		if !ok {
+			return fmt.Errorf("unknown placeholder in %s: {%s}", name, match[1])
+		}
+		if !strings.Contains(match[0], ":") {
+			continue
+		}
+		if placeholder.value == nil {
+			return fmt.Errorf("placeholder {%s} in %s does not take a format", match[1], name)
+		}
+		if _, err := parseLabelFormat(match[2]); err != nil {
+			return fmt.Errorf("placeholder %s in %s: %w", match[0], name, err)
+		}
+	}
+
+	rest := labelPlaceholderPattern.ReplaceAllString(template, "")
+	if strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("unbalanced braces in %s: %q", name, template)
+	}
+
+	return nil
+}
+
+// formatTrayLabel fills the template placeholders from the weather.
+// Placeholders that are unknown or have an invalid format are left as they
+// are.
+func formatTrayLabel(template string, weather *WeatherData, config *AppConfig) string {
+	return labelPlaceholderPattern.ReplaceAllStringFunc(template, func(text string) string {
+		match := labelPlaceholderPattern.FindStringSubmatch(text)
+		placeholder, ok := labelPlaceholders[match[1]]
+		if !ok {
+			return text
+		}
+		if placeholder.value == nil {
+			if strings.Contains(text, ":") {
+				return text
+			}
+			return placeholder.text(weather, config)
+		}
+
+		format, err := parseLabelFormat(match[2])
+		if err != nil {
+			return text
+		}
+		formatted := formatLabelNumber(placeholder.value(weather, config), format, config.Language)
+		if format.unit {
+			formatted += placeholder.unit(config)
+		}
+		return formatted
+	})
+}
+
+// GetTrayLabelPlaceholders returns the placeholder names a tray label
+// template can use
+func (w *WeatherService) GetTrayLabelPlaceholders() []string {
+	names := make([]string, 0, len(labelPlaceholders))
+	for name := range labelPlaceholders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PreviewTrayLabel formats a tray label template with the latest weather
+func (w *WeatherService) PreviewTrayLabel(template string) (string, error) {
+	if err := validateLabelTemplate("trayLabel", template); err != nil {
+		return "", err
+	}
+
+	config, err := w.app.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	weather, err := w.latestWeather()
+	if err != nil {
+		return "", err
+	}
+
+	return formatTrayLabel(template, weather, config), nil
+}
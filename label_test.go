@@ -0,0 +1,80 @@
+package main
+
+import "testing"
+
+func TestFormatTrayLabel(t *testing.T) {
+	weather := &WeatherData{
+		Location:    "Oslo",
+		Temperature: -3.46,
+		FeelsLike:   2.5,
+		Humidity:    81,
+		WindSpeed:   12.34,
+		RainChance:  65,
+		Condition:   "Snow",
+	}
+	config := (&App{}).GetDefaultConfig()
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{defaultTrayLabel, "Oslo: -3°C - Snow"},
+		{"{temp:.1}", "-3.5"},
+		{"{temp:.2,unit}", "-3.46°C"},
+		{"{temp:unit}", "-3°C"},
+		{"{temp:nounit}{unit}", "-3°C"},
+		{"{feelsLike:+}", "+3"},
+		{"{feelsLike:+.1,unit}", "+2.5°C"},
+		{"{temp:+}", "-3"},
+		{"{humidity:unit} {rain}", "81% 65"},
+		{"{wind:.1,unit}", "12.3 km/h"},
+		{"{unknown} {temp:.9}", "{unknown} {temp:.9}"},
+	}
+
+	for _, tt := range tests {
+		if got := formatTrayLabel(tt.template, weather, config); got != tt.want {
+			t.Errorf("formatTrayLabel(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTrayLabelUnitAndLanguage(t *testing.T) {
+	weather := &WeatherData{Temperature: 0.02}
+	config := (&App{}).GetDefaultConfig()
+	config.Language = "de"
+
+	if got := formatTrayLabel("{temp:+.1}", weather, config); got != "0,0" {
+		t.Errorf("signed zero = %q, want 0,0", got)
+	}
+
+	config.CustomSettings["temperatureUnit"] = "fahrenheit"
+	if got := formatTrayLabel("{temp:.1,unit}", weather, config); got != "32,0°F" {
+		t.Errorf("fahrenheit = %q, want 32,0°F", got)
+	}
+}
+
+func TestValidateLabelTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		valid    bool
+	}{
+		{defaultTrayLabel, true},
+		{"{temp:.1} {high:+,unit} {low:nounit} {wind:.2}", true},
+		{"{temp:}", true},
+		{"{temp:.4}", false},
+		{"{temp:1}", false},
+		{"{temp:-}", false},
+		{"{temp:units}", false},
+		{"{temp:.1,,unit}", false},
+		{"{location:.1}", false},
+		{"{unit:nounit}", false},
+		{"{nope}", false},
+		{"{temp", false},
+	}
+
+	for _, tt := range tests {
+		if err := validateLabelTemplate("trayLabel", tt.template); (err == nil) != tt.valid {
+			t.Errorf("validateLabelTemplate(%q) = %v, want valid %v", tt.template, err, tt.valid)
+		}
+	}
+}
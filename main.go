@@ -1,20 +1,24 @@
 package main
 
 import (
-	//"os"
 	"embed"
 	_ "embed"
-	"fmt"
 	"log"
+	"os"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
 )
 
 // Register custom events
 func init() {
 	application.RegisterEvent[*WeatherData]("trayIconUpdate")
+	application.RegisterEvent[*Appearance]("appearanceChanged")
+	application.RegisterEvent[*UpdateProgress]("updateProgress")
+	application.RegisterEvent[*UpdateInfo]("updateAvailable")
 }
 
 // Wails uses Go's `embed` package to embed the frontend files into the binary.
@@ -30,7 +34,15 @@ var trayIcon []byte
 
 // App struct to hold application state and provide utility methods
 type App struct {
-	mainWindow *application.WebviewWindow
+	mainWindow          *application.WebviewWindow
+	profilesChangedFunc func()
+	configAppliedFunc   func(config *AppConfig)
+	launchOptions       *LaunchOptions
+	window              windowState
+	alerts              alertState
+	startup             startupState
+	// configMu serialises changes to the config file
+	configMu sync.Mutex
 }
 
 // HideWindow hides the main window
@@ -47,7 +59,8 @@ func (a *App) MinimizeWindow() {
 	}
 }
 
-// PositionWindowNearTray positions the window near the system tray
+// PositionWindowNearTray positions the window near the system tray, or at
+// the position it was last moved to on the current screen
 func (a *App) PositionWindowNearTray() {
 	if a.mainWindow == nil {
 		return
@@ -60,8 +73,12 @@ func (a *App) PositionWindowNearTray() {
 		return
 	}
 
-	windowWidth := 400
-	windowHeight := 450
+	if position, ok := a.rememberedWindowPosition(screen); ok {
+		a.placeWindow(position.X, position.Y)
+		return
+	}
+
+	windowWidth, windowHeight := a.mainWindow.Size()
 	padding := 10
 
 	var x, y int
@@ -80,7 +97,7 @@ func (a *App) PositionWindowNearTray() {
 		y = padding
 	}
 
-	a.mainWindow.SetPosition(x, y)
+	a.placeWindow(x, y)
 }
 
 // main function serves as the application's entry point. It initializes the application, creates a window,
@@ -97,6 +114,45 @@ func main() {
 	appInstance := &App{}
 	weatherService := NewWeatherService(appInstance)
 
+	// Settings can be overridden by flags given before any subcommand
+	launchOptions, err := ParseLaunchOptions(os.Args[1:], os.Stderr)
+	if err != nil {
+		os.Exit(launchExitCode(err))
+	}
+	appInstance.SetLaunchOptions(launchOptions)
+
+	// Configuration subcommands run without starting the GUI
+	exitOnCommand(appInstance, launchOptions.Args)
+
+	// Autostart entries can delay the start until the desktop has settled
+	if launchOptions.LaunchDelay > 0 {
+		time.Sleep(time.Duration(launchOptions.LaunchDelay) * time.Second)
+	}
+
+	// The binary replaced by an update is kept until the next launch, or
+	// until this launch reports that the update started
+	healthPath := updateHealthPath()
+	if healthPath == "" {
+		cleanupPreviousUpdate()
+	}
+
+	if launchOptions.Profile != "" {
+		if err := appInstance.SwitchProfile(launchOptions.Profile); err != nil {
+			log.Fatalf("Failed to switch to profile %s: %v", launchOptions.Profile, err)
+		}
+	}
+
+	config, err := appInstance.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := validateConfig(config); err != nil {
+		log.Printf("Configuration problem: %v", err)
+	}
+
+	// The tray animator is created with the tray below
+	var animator *trayAnimator
+
 	app := application.New(application.Options{
 		Name:        "myWeatherApp",
 		Description: "A weather app with system tray",
@@ -110,23 +166,59 @@ func main() {
 		Mac: application.MacOptions{
 			ApplicationShouldTerminateAfterLastWindowClosed: false,
 		},
+		OnShutdown: func() {
+			if animator != nil {
+				animator.Stop()
+			}
+		},
 	})
 
+	if healthPath != "" {
+		app.Event.OnApplicationEvent(events.Common.ApplicationStarted, func(event *application.ApplicationEvent) {
+			reportUpdateHealth(healthPath)
+		})
+	}
+
 	// Create system tray
 	systray := app.SystemTray.New()
 
+	// Tray icons are set from the animator, which draws refresh and alert
+	// animations over the weather icon
+	animator = newTrayAnimator(func(iconData []byte, template bool) {
+		if runtime.GOOS == "darwin" && template {
+			systray.SetTemplateIcon(iconData)
+		} else {
+			systray.SetIcon(iconData)
+		}
+	})
+	animator.Start()
+	weatherService.SetRefreshStateFunc(animator.SetRefreshing)
+
+	// The tray menu is created once the window exists
+	var trayMenuInstance *trayMenu
+
 	// Create a function to update tray icon that can be called from weather service
 	updateTrayIconFunc := func(weather *WeatherData) {
 		log.Printf("Updating tray icon: Location=%s, Temperature=%.2f°C, Condition=%s",
 			weather.Location, weather.Temperature, weather.Condition)
 
-		iconData, err := generateTrayIconWithWeather(weather)
-		if err == nil {
-			systray.SetIcon(iconData)
-		} else {
+		config, err := appInstance.LoadConfig()
+		if err != nil {
+			log.Printf("Failed to load config: %v", err)
+			config = appInstance.GetDefaultConfig()
+		}
+
+		icon, err := renderTrayIcon(weather, config, platformTrayIconSize())
+		if err != nil {
 			log.Printf("Failed to generate tray icon: %v", err)
+		} else {
+			animator.SetFPS(config.TrayIcon.AnimationFPS)
+			animator.SetBase(icon, isTemplateTrayIcon(&config.TrayIcon))
 		}
-		systray.SetLabel(fmt.Sprintf("%s: %.0f°C - %s", weather.Location, weather.Temperature, weather.Condition))
+		systray.SetLabel(formatTrayLabel(trayLabelTemplate(config), weather, config))
+		systray.SetTooltip(formatTrayTooltip(trayTooltipTemplate(config), weather, config))
+		trayMenuInstance.SetWeather(weather)
+		appInstance.updateAlert(weather, &config.Alerts)
 	}
 
 	// Pass the update function to the weather service
@@ -146,67 +238,81 @@ func main() {
 		updateTrayIconFunc(weather)
 	}
 
+	// Create a new window with the necessary options.
+	mainWindow := app.Window.NewWithOptions(mainWindowOptions(config))
+	// Store window reference in app instance
+	appInstance.mainWindow = mainWindow
+	appInstance.attachWindowHandlers(app)
+
+	// Show and position window after a delay, unless started to the tray
+	if !launchOptions.Minimized {
+		go func() {
+			time.Sleep(300 * time.Millisecond)
+			log.Println("Positioning and showing window...")
+			appInstance.PositionWindowNearTray()
+			mainWindow.Show()
+			log.Println("Window shown and positioned")
+		}()
+	}
+
+	// Add system tray menu
+	trayMenuInstance = newTrayMenu(appInstance, app.NewMenu(), trayMenuActions{
+		Show: func() {
+			mainWindow.Show()
+			mainWindow.UnMinimise()
+			mainWindow.Focus()
+			// Position after showing the window
+			appInstance.PositionWindowNearTray()
+		},
+		Refresh: func() {
+			updateTrayIcon()
+		},
+		ProfileSwitched: func() {
+			go updateTrayIcon()
+		},
+		About: func() {
+			app.Dialog.Info().
+				SetTitle("myWeatherApp").
+				SetMessage(currentBuildInfo().String()).
+				Show()
+		},
+		Quit: func() {
+			app.Quit()
+		},
+	})
+	appInstance.SetProfilesChangedFunc(trayMenuInstance.Rebuild)
+	appInstance.SetAlertChangedFunc(func(unacknowledged bool) {
+		animator.SetAlert(unacknowledged)
+		trayMenuInstance.SetAlert(unacknowledged)
+	})
+	systray.SetMenu(trayMenuInstance.menu)
+
 	// Set initial icon
 	updateTrayIcon()
 
-	// Update tray icon periodically
+	// Update tray icon every updateInterval, following changes to it
+	refresher := newRefreshScheduler(refreshInterval(config), updateTrayIcon)
+	appInstance.SetConfigAppliedFunc(func(config *AppConfig) {
+		refresher.SetInterval(refreshInterval(config))
+	})
+	refresher.Start()
+
+	// Keep the relative "Last updated" time current
 	go func() {
-		ticker := time.NewTicker(5 * time.Minute)
+		ticker := time.NewTicker(time.Minute)
 		defer ticker.Stop()
 		for range ticker.C {
-			updateTrayIcon()
+			trayMenuInstance.RefreshLastUpdated()
 		}
 	}()
 
-	// Create a new window with the necessary options.
-	mainWindow := app.Window.NewWithOptions(application.WebviewWindowOptions{
-		Title: "Weather App",
-		Mac: application.MacWindow{
-			InvisibleTitleBarHeight: 50,
-			Backdrop:                application.MacBackdropTranslucent,
-			TitleBar:                application.MacTitleBarHiddenInset,
-		},
-		Width:            400,
-		Height:           450,
-		MinWidth:         400,
-		MinHeight:        450,
-		MaxWidth:         400,
-		MaxHeight:        450,
-		BackgroundColour: application.NewRGBA(0, 0, 0, 0),
-		URL:              "/",
-		Hidden:           true, // Start hidden
-		Frameless:        true,
+	// Check for updates once the check interval has passed since the last check
+	appInstance.StartUpdateChecks(func(info *UpdateInfo) {
+		app.Event.Emit("updateAvailable", info)
 	})
-	// Store window reference in app instance
-	appInstance.mainWindow = mainWindow
-
-	// Show and position window after a delay
-	go func() {
-		time.Sleep(300 * time.Millisecond)
-		log.Println("Positioning and showing window...")
-		appInstance.PositionWindowNearTray()
-		mainWindow.Show()
-		log.Println("Window shown and positioned")
-	}()
 
-	// Add system tray menu
-	menu := app.NewMenu()
-	menu.Add("Show Weather").OnClick(func(ctx *application.Context) {
-		mainWindow.Show()
-		mainWindow.UnMinimise()
-		mainWindow.Focus()
-		// Position after showing the window
-		appInstance.PositionWindowNearTray()
-	})
-	menu.AddSeparator()
-	menu.Add("Refresh Weather").OnClick(func(ctx *application.Context) {
-		updateTrayIcon()
-	})
-	menu.AddSeparator()
-	menu.Add("Quit").OnClick(func(ctx *application.Context) {
-		app.Quit()
-	})
-	systray.SetMenu(menu)
+	// Point the startup entry at this executable if the app was moved
+	go appInstance.verifyStartup(false)
 
 	// Run the application. This blocks until the application has been exited.
 	// Initialize single instance lock
@@ -216,7 +322,7 @@ func main() {
 	//}
 	//defer releaseSingleInstance()
 
-	err := app.Run()
+	err = app.Run()
 
 	// If an error occurred while running the application, log it and exit.
 	if err != nil {
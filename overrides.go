@@ -0,0 +1,443 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Setting sources, from lowest to highest precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// envPrefix is prepended to every environment variable override
+const envPrefix = "MYWEATHERAPP_"
+
+// nonOverridableSettings lists config fields that hold app state rather than
+// user settings
+var nonOverridableSettings = map[string]bool{
+	"activeProfile":   true,
+	"windowPositions": true,
+	// Custom settings are defined per key
+	"customSettings": true,
+}
+
+// EffectiveSetting reports the value a setting resolved to and where it came from
+type EffectiveSetting struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+	EnvVar string      `json:"envVar"`
+	Flag   string      `json:"flag"`
+}
+
+// LaunchOptions holds the command-line options parsed in main
+type LaunchOptions struct {
+	Profile   string
+	Overrides map[string]string
+	Args      []string
+	// Minimized starts with only the tray icon, as autostart entries do
+	Minimized bool
+	// LaunchDelay is the number of seconds to wait before starting
+	LaunchDelay int
+}
+
+// settingDefinition describes one overridable setting
+type settingDefinition struct {
+	key    string
+	path   []string
+	custom bool
+	typ    reflect.Type
+}
+
+// settingDefinitions lists every overridable setting: all AppConfig fields,
+// including nested ones, and the default custom settings. The list is built
+// by reflection once and shared, so callers must not modify it.
+var settingDefinitions = sync.OnceValue(func() []settingDefinition {
+	var defs []settingDefinition
+	collectSettingDefinitions(reflect.TypeOf(AppConfig{}), nil, &defs)
+
+	defaults := (&App{}).GetDefaultConfig()
+	for key, value := range defaults.CustomSettings {
+		typ := reflect.TypeOf(value)
+		if typ.Kind() == reflect.Int {
+			typ = reflect.TypeOf(float64(0)) // JSON numbers decode as float64
+		}
+		defs = append(defs, settingDefinition{key: key, path: []string{key}, custom: true, typ: typ})
+	}
+
+	sort.Slice(defs, func(i, j int) bool { return defs[i].key < defs[j].key })
+	return defs
+})
+
+// collectSettingDefinitions walks struct fields by their JSON names. It
+// panics on a field type that cannot be overridden, so a new setting cannot
+// silently lose its flag and environment variable.
+func collectSettingDefinitions(t reflect.Type, prefix []string, defs *[]settingDefinition) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonFieldName(field)
+		if name == "" {
+			continue
+		}
+
+		path := append(append([]string{}, prefix...), name)
+		key := strings.Join(path, ".")
+		if nonOverridableSettings[key] {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Float64, reflect.Slice, reflect.Map, reflect.Pointer:
+			*defs = append(*defs, settingDefinition{key: key, path: path, typ: field.Type})
+		case reflect.Struct:
+			collectSettingDefinitions(field.Type, path, defs)
+		default:
+			panic(fmt.Sprintf("setting %s has type %s, which cannot be overridden", key, field.Type))
+		}
+	}
+}
+
+// jsonFieldName returns the JSON name of a struct field, or an empty string
+// if the field is not serialised
+func jsonFieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// splitCamel splits a camelCase name into lower-case words
+func splitCamel(name string) []string {
+	var words []string
+	var current []rune
+	for _, r := range name {
+		if unicode.IsUpper(r) && len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+		current = append(current, unicode.ToLower(r))
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+// settingWords splits a dotted setting key into lower-case words
+func settingWords(key string) []string {
+	var words []string
+	for _, part := range strings.Split(key, ".") {
+		words = append(words, splitCamel(part)...)
+	}
+	return words
+}
+
+// envVarName returns the environment variable that overrides a setting,
+// e.g. windowWidth -> MYWEATHERAPP_WINDOW_WIDTH
+func envVarName(key string) string {
+	return envPrefix + strings.ToUpper(strings.Join(settingWords(key), "_"))
+}
+
+// flagName returns the command-line flag that overrides a setting,
+// e.g. windowWidth -> window-width
+func flagName(key string) string {
+	return strings.Join(settingWords(key), "-")
+}
+
+// ParseLaunchOptions parses the command-line flags given before any
+// subcommand. Every setting has a flag named after it.
+func ParseLaunchOptions(args []string, out io.Writer) (*LaunchOptions, error) {
+	flags := flag.NewFlagSet("myWeatherApp", flag.ContinueOnError)
+	flags.SetOutput(out)
+
+	options := &LaunchOptions{Overrides: make(map[string]string)}
+	flags.StringVar(&options.Profile, "profile", os.Getenv(envPrefix+"PROFILE"), "switch to a saved profile on launch")
+	flags.BoolVar(&options.Minimized, "minimized", false, "start with the window hidden")
+	flags.IntVar(&options.LaunchDelay, "launch-delay", 0, "wait this many seconds before starting")
+
+	defs := settingDefinitions()
+	flagKeys := make(map[string]string, len(defs))
+	for _, def := range defs {
+		name := flagName(def.key)
+		flagKeys[name] = def.key
+		flags.String(name, "", fmt.Sprintf("override the %s setting%s (env %s)", def.key, def.valueHint(), envVarName(def.key)))
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if options.LaunchDelay < 0 || options.LaunchDelay > maxStartupDelay {
+		err := fmt.Errorf("launch-delay must be between 0 and %d seconds", maxStartupDelay)
+		fmt.Fprintln(out, err)
+		return nil, err
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		if key, ok := flagKeys[f.Name]; ok {
+			options.Overrides[key] = f.Value.String()
+		}
+	})
+	options.Args = flags.Args()
+
+	return options, nil
+}
+
+// launchExitCode returns the exit status for an error from
+// ParseLaunchOptions: 0 after -h or --help, 2 for invalid flags
+func launchExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	return 2
+}
+
+// SetLaunchOptions stores the command-line options used to resolve settings
+func (a *App) SetLaunchOptions(options *LaunchOptions) {
+	a.launchOptions = options
+}
+
+// applyOverrides applies environment and command-line overrides to a
+// configuration, in that order, so flags take precedence
+func (a *App) applyOverrides(config *AppConfig) error {
+	for _, def := range settingDefinitions() {
+		if value, ok := os.LookupEnv(envVarName(def.key)); ok {
+			if err := setSettingValue(config, def, value); err != nil {
+				return fmt.Errorf("%s: %w", envVarName(def.key), err)
+			}
+		}
+	}
+
+	if a.launchOptions == nil {
+		return nil
+	}
+	for _, def := range settingDefinitions() {
+		if value, ok := a.launchOptions.Overrides[def.key]; ok {
+			if err := setSettingValue(config, def, value); err != nil {
+				return fmt.Errorf("--%s: %w", flagName(def.key), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isStringList reports whether the setting is a list of strings
+func (def settingDefinition) isStringList() bool {
+	return def.typ.Kind() == reflect.Slice && def.typ.Elem().Kind() == reflect.String
+}
+
+// valueHint describes how non-scalar values are written in the flag help
+func (def settingDefinition) valueHint() string {
+	switch {
+	case def.isStringList():
+		return ", comma-separated or a JSON array"
+	case def.typ.Kind() == reflect.Slice, def.typ.Kind() == reflect.Map, def.typ.Kind() == reflect.Pointer:
+		return ", as JSON"
+	default:
+		return ""
+	}
+}
+
+// parseSettingValue converts an override string to the setting's type.
+// Lists and objects are JSON; string lists may also be comma-separated.
+func parseSettingValue(def settingDefinition, raw string) (interface{}, error) {
+	switch def.typ.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Bool:
+		return strconv.ParseBool(raw)
+	case reflect.Int:
+		return strconv.Atoi(raw)
+	case reflect.Float64:
+		return strconv.ParseFloat(raw, 64)
+	case reflect.Slice, reflect.Map, reflect.Pointer:
+		if def.isStringList() && !strings.HasPrefix(strings.TrimSpace(raw), "[") {
+			return splitList(raw), nil
+		}
+		value := reflect.New(def.typ)
+		if err := json.Unmarshal([]byte(raw), value.Interface()); err != nil {
+			return nil, err
+		}
+		return value.Elem().Interface(), nil
+	default:
+		return nil, fmt.Errorf("unsupported setting type %s", def.typ)
+	}
+}
+
+// setSettingValue parses raw and stores it in the setting
+func setSettingValue(config *AppConfig, def settingDefinition, raw string) error {
+	value, err := parseSettingValue(def, raw)
+	if err != nil {
+		return fmt.Errorf("invalid value %q", raw)
+	}
+
+	if def.custom {
+		if config.CustomSettings == nil {
+			config.CustomSettings = make(map[string]interface{})
+		}
+		config.CustomSettings[def.key] = value
+		return nil
+	}
+
+	field, ok := settingField(config, def)
+	if !ok {
+		return fmt.Errorf("unknown setting %s", def.key)
+	}
+	field.Set(reflect.ValueOf(value).Convert(field.Type()))
+	return nil
+}
+
+// getSettingValue returns the current value of a setting
+func getSettingValue(config *AppConfig, def settingDefinition) interface{} {
+	if def.custom {
+		return config.CustomSettings[def.key]
+	}
+
+	field, ok := settingField(config, def)
+	if !ok {
+		return nil
+	}
+	return field.Interface()
+}
+
+// settingField resolves a setting's JSON path to a struct field
+func settingField(config *AppConfig, def settingDefinition) (reflect.Value, bool) {
+	value := reflect.ValueOf(config).Elem()
+	for _, name := range def.path {
+		found := false
+		for i := 0; i < value.NumField(); i++ {
+			if jsonFieldName(value.Type().Field(i)) == name {
+				value = value.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, false
+		}
+	}
+	return value, true
+}
+
+// fileHasSetting reports whether the raw config file sets a key
+func fileHasSetting(raw map[string]interface{}, def settingDefinition) bool {
+	if def.custom {
+		custom, _ := raw["customSettings"].(map[string]interface{})
+		_, ok := custom[def.key]
+		return ok
+	}
+
+	current := raw
+	for i, name := range def.path {
+		value, ok := current[name]
+		if !ok {
+			return false
+		}
+		if i == len(def.path)-1 {
+			return true
+		}
+		if current, ok = value.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return false
+}
+
+// GetEffectiveSettings returns every setting with its effective value and
+// the source it came from
+func (a *App) GetEffectiveSettings() ([]EffectiveSetting, error) {
+	config, err := a.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string]interface{})
+	configPath, err := a.GetConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	if data, err := os.ReadFile(configPath); err == nil {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+	}
+
+	defs := settingDefinitions()
+	settings := make([]EffectiveSetting, 0, len(defs))
+	for _, def := range defs {
+		setting := EffectiveSetting{
+			Key:    def.key,
+			Value:  getSettingValue(config, def),
+			Source: SourceDefault,
+			EnvVar: envVarName(def.key),
+			Flag:   "--" + flagName(def.key),
+		}
+
+		if a.launchOptions != nil {
+			if _, ok := a.launchOptions.Overrides[def.key]; ok {
+				setting.Source = SourceFlag
+			}
+		}
+		if setting.Source == SourceDefault {
+			if _, ok := os.LookupEnv(setting.EnvVar); ok {
+				setting.Source = SourceEnv
+			} else if fileHasSetting(raw, def) {
+				setting.Source = SourceFile
+			}
+		}
+
+		settings = append(settings, setting)
+	}
+
+	return settings, nil
+}
+
+// GetEffectiveSetting returns the effective value of one setting and the
+// source it came from
+func (a *App) GetEffectiveSetting(key string) (*EffectiveSetting, error) {
+	settings, err := a.GetEffectiveSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, setting := range settings {
+		if setting.Key == key {
+			return &setting, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown setting: %s", key)
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(raw string) []string {
+	items := []string{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestSettingDefinitionsCoverLists(t *testing.T) {
+	keys := make(map[string]bool)
+	for _, def := range settingDefinitions() {
+		keys[def.key] = true
+	}
+
+	for _, key := range []string{"theme", "trayIcon.style", "trayIcon.palette", "updates.ignoredVersions", "startup.args", "weatherLocation"} {
+		if !keys[key] {
+			t.Errorf("setting %s has no override", key)
+		}
+	}
+	for _, key := range []string{"activeProfile", "windowPositions", "customSettings"} {
+		if keys[key] {
+			t.Errorf("setting %s should not be overridable", key)
+		}
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	setTestHome(t)
+
+	tests := []struct {
+		name  string
+		env   map[string]string
+		flags []string
+		check func(config *AppConfig) bool
+	}{
+		{
+			name:  "scalar flag",
+			flags: []string{"--window-width", "500"},
+			check: func(config *AppConfig) bool { return config.WindowWidth == 500 },
+		},
+		{
+			name: "comma-separated list",
+			env:  map[string]string{"MYWEATHERAPP_UPDATES_IGNORED_VERSIONS": "v1.2.0, v1.3.0,"},
+			check: func(config *AppConfig) bool {
+				return reflect.DeepEqual(config.Updates.IgnoredVersions, []string{"v1.2.0", "v1.3.0"})
+			},
+		},
+		{
+			name:  "JSON list keeps commas",
+			flags: []string{"--startup-args", `["--profile", "a,b"]`},
+			check: func(config *AppConfig) bool {
+				return reflect.DeepEqual(config.Startup.Args, []string{"--profile", "a,b"})
+			},
+		},
+		{
+			name: "JSON object",
+			env:  map[string]string{"MYWEATHERAPP_TRAY_ICON_PALETTE": `{"conditions": {"rain": "#1565c0"}}`},
+			check: func(config *AppConfig) bool {
+				return config.TrayIcon.Palette != nil && config.TrayIcon.Palette.Conditions["rain"] == "#1565c0"
+			},
+		},
+		{
+			name:  "flag wins over env",
+			env:   map[string]string{"MYWEATHERAPP_THEME": "dark"},
+			flags: []string{"--theme", "system"},
+			check: func(config *AppConfig) bool { return config.Theme == "system" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			options, err := ParseLaunchOptions(tt.flags, io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+			app := &App{}
+			app.SetLaunchOptions(options)
+
+			config, err := app.LoadConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.check(config) {
+				t.Errorf("override not applied: %+v", config)
+			}
+		})
+	}
+}
+
+func TestApplyOverridesRejectsInvalidJSON(t *testing.T) {
+	setTestHome(t)
+	t.Setenv("MYWEATHERAPP_TRAY_ICON_PALETTE", `{"conditions": `)
+
+	if _, err := (&App{}).LoadConfig(); err == nil {
+		t.Error("LoadConfig accepted invalid JSON override")
+	}
+}
+
+func TestLaunchExitCode(t *testing.T) {
+	for _, args := range [][]string{{"-h"}, {"--help"}} {
+		_, err := ParseLaunchOptions(args, io.Discard)
+		if code := launchExitCode(err); code != 0 {
+			t.Errorf("%v exits with %d, want 0", args, code)
+		}
+	}
+
+	_, err := ParseLaunchOptions([]string{"--no-such-flag"}, io.Discard)
+	if code := launchExitCode(err); code != 2 {
+		t.Errorf("unknown flag exits with %d, want 2", code)
+	}
+}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Import modes accepted by ImportConfig
+const (
+	ImportModeMerge   = "merge"
+	ImportModeReplace = "replace"
+)
+
+// configExportFormat identifies files written by ExportConfig
+const configExportFormat = "myWeatherApp-config"
+
+// configExportVersion is bumped when the export layout changes
+const configExportVersion = 1
+
+// ConfigExport is the portable file format used to share a configuration
+type ConfigExport struct {
+	Format     string     `json:"format"`
+	Version    int        `json:"version"`
+	AppVersion string     `json:"appVersion"`
+	ExportedAt string     `json:"exportedAt"`
+	Config     *AppConfig `json:"config"`
+}
+
+// profileNamePattern restricts profile names to safe file names
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// SetProfilesChangedFunc sets the function called after profiles are
+// created, switched or deleted
+func (a *App) SetProfilesChangedFunc(changedFunc func()) {
+	a.profilesChangedFunc = changedFunc
+}
+
+// notifyProfilesChanged calls the profiles changed hook if one is set
+func (a *App) notifyProfilesChanged() {
+	if a.profilesChangedFunc != nil {
+		a.profilesChangedFunc()
+	}
+}
+
+// ExportConfig writes the current configuration to a portable file
+func (a *App) ExportConfig(path string) error {
+	config, err := a.loadStoredConfig()
+	if err != nil {
+		return err
+	}
+
+	// The active profile only exists on this machine
+	config.ActiveProfile = ""
+
+	export := ConfigExport{
+		Format:     configExportFormat,
+		Version:    configExportVersion,
+		AppVersion: currentVersion(),
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Config:     config,
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// ImportConfig reads a configuration exported by ExportConfig and applies it
+// using merge or replace semantics. The resulting configuration is returned.
+func (a *App) ImportConfig(path string, mode string) (*AppConfig, error) {
+	imported, err := readConfigExport(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config *AppConfig
+	switch mode {
+	case ImportModeReplace:
+		config = a.GetDefaultConfig()
+	case ImportModeMerge, "":
+		config, err = a.loadStoredConfig()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown import mode: %s", mode)
+	}
+
+	// Unmarshalling over the base only replaces the values present in the
+	// file, and merges custom settings key by key
+	if err := json.Unmarshal(imported, config); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	// The imported settings are not saved in any local profile yet
+	config.ActiveProfile = ""
+	resetMissingPaths(config)
+
+	if err := validateConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if err := a.SaveConfig(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// resetMissingPaths clears file paths from another machine that do not
+// exist here, so they fall back to their defaults instead of failing the
+// import
+func resetMissingPaths(config *AppConfig) {
+	if config.TrayIcon.FontFile == "" {
+		return
+	}
+	if _, err := os.Stat(config.TrayIcon.FontFile); err != nil {
+		log.Printf("Ignoring trayIcon.fontFile %s from the imported configuration: %v", config.TrayIcon.FontFile, err)
+		config.TrayIcon.FontFile = ""
+	}
+}
+
+// readConfigExport loads and checks an exported configuration file and
+// returns its raw config section
+func readConfigExport(path string) (json.RawMessage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var export struct {
+		Format  string          `json:"format"`
+		Version int             `json:"version"`
+		Config  json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
+	}
+
+	if export.Format != configExportFormat {
+		return nil, fmt.Errorf("not a myWeatherApp configuration file")
+	}
+	if export.Version > configExportVersion {
+		return nil, fmt.Errorf("configuration file version %d is newer than supported version %d",
+			export.Version, configExportVersion)
+	}
+	if len(export.Config) == 0 || string(export.Config) == "null" {
+		return nil, fmt.Errorf("configuration file has no config section")
+	}
+
+	return export.Config, nil
+}
+
+// validateConfig checks that a configuration only holds supported values
+func validateConfig(config *AppConfig) error {
+	switch config.Theme {
+	case "light", "dark", "system":
+	default:
+		return fmt.Errorf("unsupported theme: %q", config.Theme)
+	}
+
+	if len(config.Language) < 2 {
+		return fmt.Errorf("unsupported language: %q", config.Language)
+	}
+
+	if config.WindowWidth < 200 || config.WindowWidth > 4000 {
+		return fmt.Errorf("window width out of range: %d", config.WindowWidth)
+	}
+	if config.WindowHeight < 200 || config.WindowHeight > 4000 {
+		return fmt.Errorf("window height out of range: %d", config.WindowHeight)
+	}
+
+	switch config.SecretsBackend {
+	case SecretsBackendAuto, SecretsBackendKeyring, SecretsBackendFile:
+	default:
+		return fmt.Errorf("unsupported secretsBackend: %q", config.SecretsBackend)
+	}
+	if config.GitHubToken != "" && !secretNamePattern.MatchString(string(config.GitHubToken)) {
+		return fmt.Errorf("githubToken must be the name of a stored secret")
+	}
+
+	if err := validateTrayIconConfig(&config.TrayIcon); err != nil {
+		return err
+	}
+
+	if err := validateLabelTemplate("trayLabel", config.TrayLabel); err != nil {
+		return err
+	}
+	if err := validateLabelTemplate("trayTooltip", config.TrayTooltip); err != nil {
+		return err
+	}
+
+	if err := validateUpdateConfig(&config.Updates); err != nil {
+		return err
+	}
+	if err := validateStartupConfig(&config.Startup); err != nil {
+		return err
+	}
+	if err := validateAlertConfig(&config.Alerts); err != nil {
+		return err
+	}
+
+	if value, ok := config.CustomSettings["weatherLocation"]; ok {
+		location, isString := value.(string)
+		if !isString || strings.TrimSpace(location) == "" {
+			return fmt.Errorf("weatherLocation must be a non-empty string")
+		}
+	}
+
+	if value, ok := config.CustomSettings["updateInterval"]; ok {
+		interval, isNumber := value.(float64)
+		if intValue, isInt := value.(int); isInt {
+			interval, isNumber = float64(intValue), true
+		}
+		if !isNumber || interval < 60 {
+			return fmt.Errorf("updateInterval must be a number of seconds >= 60")
+		}
+	}
+
+	if value, ok := config.CustomSettings["temperatureUnit"]; ok {
+		switch value {
+		case TemperatureUnitCelsius, TemperatureUnitFahrenheit:
+		default:
+			return fmt.Errorf("unsupported temperatureUnit: %v", value)
+		}
+	}
+
+	return nil
+}
+
+// getProfilesDir returns the directory holding named profiles
+func (a *App) getProfilesDir() (string, error) {
+	configPath, err := a.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+
+	profilesDir := filepath.Join(filepath.Dir(configPath), "profiles")
+	if err := os.MkdirAll(profilesDir, 0755); err != nil {
+		return "", err
+	}
+
+	return profilesDir, nil
+}
+
+// getProfilePath returns the file path for a named profile
+func (a *App) getProfilePath(name string) (string, error) {
+	if !profileNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid profile name: %q", name)
+	}
+
+	profilesDir, err := a.getProfilesDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(profilesDir, name+".json"), nil
+}
+
+// ListProfiles returns the names of all saved profiles
+func (a *App) ListProfiles() ([]string, error) {
+	profilesDir, err := a.getProfilesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(profilesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if ok && !entry.IsDir() && profileNamePattern.MatchString(name) {
+			profiles = append(profiles, name)
+		}
+	}
+	sort.Strings(profiles)
+
+	return profiles, nil
+}
+
+// GetActiveProfile returns the name of the active profile, or an empty
+// string if no profile is active
+func (a *App) GetActiveProfile() (string, error) {
+	config, err := a.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return config.ActiveProfile, nil
+}
+
+// SaveProfile stores the current configuration as a named profile and makes
+// it the active profile
+func (a *App) SaveProfile(name string) error {
+	config, err := a.loadStoredConfig()
+	if err != nil {
+		return err
+	}
+
+	config.ActiveProfile = name
+	if err := a.writeProfile(name, config); err != nil {
+		return err
+	}
+
+	if err := a.SaveConfig(config); err != nil {
+		return err
+	}
+
+	a.notifyProfilesChanged()
+	return nil
+}
+
+// SwitchProfile makes a saved profile the current configuration. The current
+// configuration is stored back to the active profile first so no changes are
+// lost. Switching to the active profile keeps the current configuration.
+func (a *App) SwitchProfile(name string) error {
+	profilePath, err := a.getProfilePath(name)
+	if err != nil {
+		return err
+	}
+
+	current, err := a.loadStoredConfig()
+	if err != nil {
+		return err
+	}
+	if current.ActiveProfile == name {
+		return nil
+	}
+
+	data, err := os.ReadFile(profilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("profile not found: %s", name)
+		}
+		return err
+	}
+
+	profile := a.GetDefaultConfig()
+	if err := json.Unmarshal(data, profile); err != nil {
+		return fmt.Errorf("failed to parse profile %s: %w", name, err)
+	}
+	if err := validateConfig(profile); err != nil {
+		return fmt.Errorf("invalid profile %s: %w", name, err)
+	}
+
+	if current.ActiveProfile != "" {
+		if err := a.writeProfile(current.ActiveProfile, current); err != nil {
+			return err
+		}
+	}
+
+	profile.ActiveProfile = name
+	if err := a.SaveConfig(profile); err != nil {
+		return err
+	}
+
+	a.notifyProfilesChanged()
+	return nil
+}
+
+// DeleteProfile removes a saved profile. The current configuration is kept
+// when the active profile is deleted.
+func (a *App) DeleteProfile(name string) error {
+	profilePath, err := a.getProfilePath(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(profilePath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("profile not found: %s", name)
+		}
+		return err
+	}
+
+	config, err := a.loadStoredConfig()
+	if err != nil {
+		return err
+	}
+	if config.ActiveProfile == name {
+		config.ActiveProfile = ""
+		if err := a.SaveConfig(config); err != nil {
+			return err
+		}
+	}
+
+	a.notifyProfilesChanged()
+	return nil
+}
+
+// writeProfile saves a configuration to a named profile file
+func (a *App) writeProfile(name string, config *AppConfig) error {
+	profilePath, err := a.getProfilePath(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(profilePath, data, 0644)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setTestHome points the home directory at a temporary directory
+func setTestHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	return home
+}
+
+func TestSwitchProfileValidates(t *testing.T) {
+	setTestHome(t)
+	app := &App{}
+
+	if err := app.SaveProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SwitchProfile("work"); err != nil {
+		t.Fatalf("SwitchProfile(work) = %v", err)
+	}
+
+	profilesDir, err := app.getProfilesDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken := `{"theme": "purple", "windowWidth": 400, "windowHeight": 450}`
+	if err := os.WriteFile(filepath.Join(profilesDir, "broken.json"), []byte(broken), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = app.SwitchProfile("broken")
+	if err == nil || !strings.Contains(err.Error(), "invalid profile broken") {
+		t.Fatalf("SwitchProfile(broken) = %v, want invalid profile error", err)
+	}
+
+	active, err := app.GetActiveProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active != "work" {
+		t.Errorf("active profile = %q after rejected switch, want work", active)
+	}
+}
+
+func TestSwitchToActiveProfileKeepsChanges(t *testing.T) {
+	setTestHome(t)
+	app := &App{}
+
+	if err := app.SaveProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SetSetting("weatherLocation", "Oslo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SwitchProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	location, err := app.GetSetting("weatherLocation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location != "Oslo" {
+		t.Errorf("weatherLocation = %v after switching to the active profile, want Oslo", location)
+	}
+}
+
+func TestImportConfigFromAnotherMachine(t *testing.T) {
+	home := setTestHome(t)
+	app := &App{}
+
+	if err := app.SaveProfile("home"); err != nil {
+		t.Fatal(err)
+	}
+
+	export := `{
+  "format": "myWeatherApp-config",
+  "version": 1,
+  "config": {
+    "activeProfile": "laptop",
+    "windowWidth": 500,
+    "trayIcon": {"style": "number", "fontFile": "/Users/someone/Fonts/Missing.ttf"}
+  }
+}`
+	path := filepath.Join(home, "export.json")
+	if err := os.WriteFile(path, []byte(export), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := app.ImportConfig(path, ImportModeMerge)
+	if err != nil {
+		t.Fatalf("ImportConfig = %v", err)
+	}
+	if config.ActiveProfile != "" {
+		t.Errorf("activeProfile = %q, want it cleared", config.ActiveProfile)
+	}
+	if config.TrayIcon.FontFile != "" {
+		t.Errorf("fontFile = %q, want the missing path reset", config.TrayIcon.FontFile)
+	}
+	if config.WindowWidth != 500 {
+		t.Errorf("windowWidth = %d, want 500", config.WindowWidth)
+	}
+}
+
+func TestExportConfigOmitsActiveProfile(t *testing.T) {
+	home := setTestHome(t)
+	app := &App{}
+
+	if err := app.SaveProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(home, "export.json")
+	if err := app.ExportConfig(path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "activeProfile") {
+		t.Errorf("export contains the active profile:\n%s", data)
+	}
+}
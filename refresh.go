@@ -0,0 +1,89 @@
+package main
+
+import "time"
+
+// defaultRefreshInterval is used when updateInterval is not set
+const defaultRefreshInterval = 5 * time.Minute
+
+// minRefreshInterval is the shortest updateInterval accepted
+const minRefreshInterval = time.Minute
+
+// refreshInterval returns how often the weather is refreshed, from the
+// updateInterval custom setting in seconds
+func refreshInterval(config *AppConfig) time.Duration {
+	var seconds float64
+	switch value := config.CustomSettings["updateInterval"].(type) {
+	case float64:
+		seconds = value
+	case int:
+		seconds = float64(value)
+	default:
+		return defaultRefreshInterval
+	}
+
+	interval := time.Duration(seconds * float64(time.Second))
+	if interval < minRefreshInterval {
+		return minRefreshInterval
+	}
+	return interval
+}
+
+// refreshScheduler calls refresh once per interval. SetInterval restarts
+// the wait when the interval changes.
+type refreshScheduler struct {
+	refresh   func()
+	intervals chan time.Duration
+	after     func(time.Duration) <-chan time.Time
+}
+
+// newRefreshScheduler creates a scheduler that calls refresh every interval
+// once started
+func newRefreshScheduler(interval time.Duration, refresh func()) *refreshScheduler {
+	s := &refreshScheduler{
+		refresh:   refresh,
+		intervals: make(chan time.Duration, 1),
+		after:     time.After,
+	}
+	s.intervals <- interval
+	return s
+}
+
+// Start runs the scheduler until the app exits
+func (s *refreshScheduler) Start() {
+	go s.run()
+}
+
+// SetInterval changes the refresh interval without blocking. Only the
+// latest interval is kept if the scheduler is busy refreshing.
+func (s *refreshScheduler) SetInterval(interval time.Duration) {
+	for {
+		select {
+		case s.intervals <- interval:
+			return
+		default:
+		}
+		select {
+		case <-s.intervals:
+		default:
+		}
+	}
+}
+
+// run waits for the interval to pass, refreshing each time it does
+func (s *refreshScheduler) run() {
+	interval := <-s.intervals
+	wait := s.after(interval)
+	for {
+		select {
+		case <-wait:
+			s.refresh()
+			wait = s.after(interval)
+		case next := <-s.intervals:
+			// Saving unrelated settings keeps the current wait
+			if next != interval {
+				interval = next
+				wait = s.after(interval)
+			}
+		}
+	}
+}
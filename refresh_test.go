@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  time.Duration
+	}{
+		{"unset", nil, defaultRefreshInterval},
+		{"int from defaults", 300, 5 * time.Minute},
+		{"float from JSON", float64(900), 15 * time.Minute},
+		{"below the minimum", float64(10), minRefreshInterval},
+		{"not a number", "often", defaultRefreshInterval},
+	}
+
+	for _, tt := range tests {
+		config := &AppConfig{CustomSettings: map[string]interface{}{}}
+		if tt.value != nil {
+			config.CustomSettings["updateInterval"] = tt.value
+		}
+		if got := refreshInterval(config); got != tt.want {
+			t.Errorf("%s: refreshInterval = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRefreshSchedulerFollowsInterval(t *testing.T) {
+	waits := make(chan time.Duration, 4)
+	ticks := make(chan time.Time)
+	refreshed := make(chan struct{}, 4)
+
+	scheduler := newRefreshScheduler(5*time.Minute, func() { refreshed <- struct{}{} })
+	scheduler.after = func(d time.Duration) <-chan time.Time {
+		waits <- d
+		return ticks
+	}
+	scheduler.Start()
+
+	expectWait := func(want time.Duration) {
+		t.Helper()
+		select {
+		case got := <-waits:
+			if got != want {
+				t.Errorf("scheduler waits %v, want %v", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("scheduler did not wait for %v", want)
+		}
+	}
+
+	expectWait(5 * time.Minute)
+	ticks <- time.Now()
+	<-refreshed
+	expectWait(5 * time.Minute)
+
+	// A new interval restarts the wait, while the same interval keeps it
+	scheduler.SetInterval(15 * time.Minute)
+	expectWait(15 * time.Minute)
+	scheduler.SetInterval(15 * time.Minute)
+	ticks <- time.Now()
+	<-refreshed
+	expectWait(15 * time.Minute)
+
+	select {
+	case got := <-waits:
+		t.Errorf("unexpected wait for %v", got)
+	default:
+	}
+}
+
+func TestUpdateCheckDue(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	last := &UpdateCheckResult{CheckedAt: now.Add(-6 * time.Hour)}
+
+	if updateCheckDue(last, now, updateCheckInterval(&UpdateConfig{})) {
+		t.Error("check due after 6 hours with the daily default")
+	}
+	if !updateCheckDue(last, now, updateCheckInterval(&UpdateConfig{CheckIntervalHours: 4})) {
+		t.Error("check not due after 6 hours with a 4 hour interval")
+	}
+	if !updateCheckDue(nil, now, CheckInterval) {
+		t.Error("first check not due")
+	}
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Release notes are rendered from the Markdown GitHub release bodies use.
+// The renderer escapes all input and only emits the tags it generates
+// itself, so raw HTML in the notes is shown as text and never executed.
+// Links are kept only for http and https URLs.
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	unorderedPattern   = regexp.MustCompile(`^\s{0,3}[-*+]\s+(.*)$`)
+	orderedPattern     = regexp.MustCompile(`^\s{0,3}\d{1,9}[.)]\s+(.*)$`)
+	rulePattern        = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	fencePattern       = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+	blockquotePattern  = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	listContinuePrefix = "  "
+)
+
+// RenderReleaseNotes converts release notes Markdown to sanitised HTML for
+// a frontend to display
+func (a *App) RenderReleaseNotes(markdown string) string {
+	return renderMarkdown(markdown)
+}
+
+// renderMarkdown renders headings, paragraphs, lists, block quotes, code
+// blocks, rules and inline code, emphasis and links
+func renderMarkdown(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	var paragraph []string
+	listTag := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + renderInline(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			out.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			out.WriteString("<" + tag + ">\n")
+			listTag = tag
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if fence := fencePattern.FindStringSubmatch(line); fence != nil {
+			flushParagraph()
+			closeList()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence[1]); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			flushParagraph()
+			// A blank line inside a list only ends it if no item follows
+			if listTag != "" && !nextIsListItem(lines, i+1) {
+				closeList()
+			}
+			continue
+		}
+
+		if rulePattern.MatchString(line) {
+			flushParagraph()
+			closeList()
+			out.WriteString("<hr>\n")
+			continue
+		}
+
+		if heading := headingPattern.FindStringSubmatch(line); heading != nil {
+			flushParagraph()
+			closeList()
+			tag := "h" + string(rune('0'+len(heading[1])))
+			out.WriteString("<" + tag + ">" + renderInline(heading[2]) + "</" + tag + ">\n")
+			continue
+		}
+
+		if quote := blockquotePattern.FindStringSubmatch(line); quote != nil {
+			flushParagraph()
+			closeList()
+			quoted := []string{quote[1]}
+			for i+1 < len(lines) {
+				next := blockquotePattern.FindStringSubmatch(lines[i+1])
+				if next == nil {
+					break
+				}
+				quoted = append(quoted, next[1])
+				i++
+			}
+			out.WriteString("<blockquote>\n" + renderMarkdown(strings.Join(quoted, "\n")) + "</blockquote>\n")
+			continue
+		}
+
+		if item := unorderedPattern.FindStringSubmatch(line); item != nil {
+			flushParagraph()
+			openList("ul")
+			out.WriteString("<li>" + renderInline(listItemText(lines, &i, item[1])) + "</li>\n")
+			continue
+		}
+		if item := orderedPattern.FindStringSubmatch(line); item != nil {
+			flushParagraph()
+			openList("ol")
+			out.WriteString("<li>" + renderInline(listItemText(lines, &i, item[1])) + "</li>\n")
+			continue
+		}
+
+		closeList()
+		paragraph = append(paragraph, strings.TrimSpace(line))
+	}
+
+	flushParagraph()
+	closeList()
+	return out.String()
+}
+
+// nextIsListItem reports whether lines[i] starts a list item
+func nextIsListItem(lines []string, i int) bool {
+	return i < len(lines) && (unorderedPattern.MatchString(lines[i]) || orderedPattern.MatchString(lines[i]))
+}
+
+// listItemText joins a list item with its indented continuation lines,
+// advancing *i past them. Nested items are flattened into the item.
+func listItemText(lines []string, i *int, first string) string {
+	text := []string{first}
+	for *i+1 < len(lines) && strings.HasPrefix(lines[*i+1], listContinuePrefix) && strings.TrimSpace(lines[*i+1]) != "" {
+		*i++
+		text = append(text, strings.TrimSpace(lines[*i]))
+	}
+	return strings.Join(text, "\n")
+}
+
+// renderInline renders code spans, links, emphasis and bare URLs in a line
+// of text, escaping everything else
+func renderInline(text string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_[]()#+-.!<>", rune(rest[1])):
+			out.WriteString(html.EscapeString(rest[1:2]))
+			i += 2
+			continue
+
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				out.WriteString("<code>" + html.EscapeString(rest[1:1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+
+		case rest[0] == '[':
+			if label, target, n, ok := parseLink(rest); ok {
+				if href, err := validateBrowserURL(target); err == nil {
+					out.WriteString(`<a href="` + html.EscapeString(href) + `" rel="noopener noreferrer">` + renderInline(label) + "</a>")
+				} else {
+					out.WriteString(renderInline(label))
+				}
+				i += n
+				continue
+			}
+
+		case rest[0] == '<':
+			// Autolinks such as <https://example.com>
+			if end := strings.IndexByte(rest, '>'); end > 0 {
+				if href, err := validateBrowserURL(rest[1:end]); err == nil && !strings.ContainsAny(rest[1:end], " \t\n") {
+					out.WriteString(`<a href="` + html.EscapeString(href) + `" rel="noopener noreferrer">` + html.EscapeString(rest[1:end]) + "</a>")
+					i += end + 1
+					continue
+				}
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if end := indexClosing(rest[2:], rest[:2]); end > 0 {
+				out.WriteString("<strong>" + renderInline(rest[2:2+end]) + "</strong>")
+				i += end + 4
+				continue
+			}
+
+		case rest[0] == '*' || rest[0] == '_' && (i == 0 || !isWordByte(text[i-1])):
+			end := indexClosing(rest[1:], rest[:1])
+			closesWord := end > 0 && (rest[0] == '*' || 2+end >= len(rest) || !isWordByte(rest[2+end]))
+			if closesWord && rest[1] != ' ' {
+				out.WriteString("<em>" + renderInline(rest[1:1+end]) + "</em>")
+				i += end + 2
+				continue
+			}
+
+		case (strings.HasPrefix(rest, "https://") || strings.HasPrefix(rest, "http://")) && (i == 0 || !isWordByte(text[i-1])):
+			n := bareURLLength(rest)
+			if href, err := validateBrowserURL(rest[:n]); err == nil {
+				out.WriteString(`<a href="` + html.EscapeString(href) + `" rel="noopener noreferrer">` + html.EscapeString(rest[:n]) + "</a>")
+				i += n
+				continue
+			}
+
+		case rest[0] == '\n':
+			out.WriteString("<br>\n")
+			i++
+			continue
+		}
+
+		out.WriteString(html.EscapeString(rest[:1]))
+		i++
+	}
+
+	return out.String()
+}
+
+// indexClosing returns the index of the delimiter closing an emphasis in
+// s, or -1. Code spans, escapes and nested strong emphasis are skipped, so
+// "*see `a*b`*" and "*a **b** c*" close at their last "*".
+func indexClosing(s, delim string) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				i += end + 1
+			}
+		case len(delim) == 1 && strings.HasPrefix(s[i:], delim+delim):
+			if end := indexClosing(s[i+2:], delim+delim); end > 0 {
+				i += end + 3
+				continue
+			}
+			return i
+		case strings.HasPrefix(s[i:], delim):
+			return i
+		}
+	}
+	return -1
+}
+
+// parseLink parses "[label](target)" at the start of s and returns the
+// number of bytes it spans
+func parseLink(s string) (label, target string, n int, ok bool) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				if i+1 >= len(s) || s[i+1] != '(' {
+					return "", "", 0, false
+				}
+				end := closingParen(s[i+2:])
+				if end < 0 {
+					return "", "", 0, false
+				}
+				// Drop an optional title: [label](url "title")
+				target, _, _ = strings.Cut(strings.TrimSpace(s[i+2:i+2+end]), " ")
+				return s[1:i], strings.Trim(target, "<>"), i + 3 + end, true
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+// closingParen returns the index of the ")" closing an already opened
+// parenthesis in s, or -1
+func closingParen(s string) int {
+	depth := 1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// bareURLLength returns the length of the URL at the start of s, leaving
+// out trailing punctuation that usually ends a sentence
+func bareURLLength(s string) int {
+	n := strings.IndexAny(s, " \t\n<>\"")
+	if n < 0 {
+		n = len(s)
+	}
+	for n > 0 && strings.ContainsRune(".,:;!?)'", rune(s[n-1])) {
+		// Keep a closing parenthesis that matches one in the URL
+		if s[n-1] == ')' && strings.Count(s[:n], "(") >= strings.Count(s[:n], ")") {
+			break
+		}
+		n--
+	}
+	return n
+}
+
+// isWordByte reports whether b is an ASCII letter or digit
+func isWordByte(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z'
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		// Raw HTML is shown as text
+		{"script", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"img onerror", `<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>\n"},
+		{"html in a quote", "> quote <b>", "<blockquote>\n<p>quote &lt;b&gt;</p>\n</blockquote>\n"},
+		{"html in a code block", "```\n<script>\n```", "<pre><code>&lt;script&gt;</code></pre>\n"},
+
+		// Only http and https links are kept
+		{"javascript link", "[click](javascript:alert(1))", "<p>click</p>\n"},
+		{"mixed case scheme", "[click](JaVaScRiPt:alert(1))", "<p>click</p>\n"},
+		{"padded scheme", "[click]( javascript:alert(1) )", "<p>click</p>\n"},
+		{"tab in scheme", "[click](java\tscript:alert(1))", "<p>click</p>\n"},
+		{"entity in scheme", "[click](&#106;avascript:alert(1))", "<p>click</p>\n"},
+		{"data link", "[click](data:text/html;base64,PHNjcmlwdD4=)", "<p>click</p>\n"},
+		{"javascript autolink", "<javascript:alert(1)>", "<p>&lt;javascript:alert(1)&gt;</p>\n"},
+		{"autolink with credentials", "<https://user:pw@evil.example>", "<p>&lt;https://user:pw@evil.example&gt;</p>\n"},
+		{"https link", "[notes](https://example.com/notes)", `<p><a href="https://example.com/notes" rel="noopener noreferrer">notes</a></p>` + "\n"},
+
+		// Quotes and ampersands cannot break out of attributes
+		{"quotes and ampersands", `[a "b" & c](https://example.com/?q="x"&y=1)`,
+			`<p><a href="https://example.com/?q=&#34;x&#34;&amp;y=1" rel="noopener noreferrer">a &#34;b&#34; &amp; c</a></p>` + "\n"},
+		{"attribute injection", `[x](https://example.com/" onmouseover="alert(1))`,
+			`<p><a href="https://example.com/%22" rel="noopener noreferrer">x</a></p>` + "\n"},
+		{"quote ends a bare URL", `https://example.com/a?b="c"`,
+			`<p><a href="https://example.com/a?b=" rel="noopener noreferrer">https://example.com/a?b=</a>&#34;c&#34;</p>` + "\n"},
+
+		// Nesting
+		{"emphasis in strong", "**bold _italic_ text**", "<p><strong>bold <em>italic</em> text</strong></p>\n"},
+		{"strong in emphasis", "*a **b** c*", "<p><em>a <strong>b</strong> c</em></p>\n"},
+		{"code in emphasis", "*see `a*b`*", "<p><em>see <code>a*b</code></em></p>\n"},
+		{"markup in code", "`<b>` and `**not bold**`", "<p><code>&lt;b&gt;</code> and <code>**not bold**</code></p>\n"},
+
+		// Unterminated constructs stay text
+		{"unterminated strong", "**bold", "<p>**bold</p>\n"},
+		{"unterminated emphasis", "*em", "<p>*em</p>\n"},
+		{"unterminated code", "`code", "<p>`code</p>\n"},
+		{"unterminated link", "[link](https://example.com",
+			`<p>[link](<a href="https://example.com" rel="noopener noreferrer">https://example.com</a></p>` + "\n"},
+		{"unterminated autolink", "<https://example.com",
+			`<p>&lt;<a href="https://example.com" rel="noopener noreferrer">https://example.com</a></p>` + "\n"},
+		{"unterminated fence", "```\n<script>", "<pre><code>&lt;script&gt;</code></pre>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderMarkdown(tt.markdown)
+			if got != tt.want {
+				t.Errorf("renderMarkdown(%q)\n got %q\nwant %q", tt.markdown, got, tt.want)
+			}
+			if strings.Contains(strings.ToLower(got), "<script") || strings.Contains(got, "<img") {
+				t.Errorf("renderMarkdown(%q) emitted raw HTML: %q", tt.markdown, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Release channels, from most to least stable
+const (
+	ReleaseChannelStable  = "stable"
+	ReleaseChannelBeta    = "beta"
+	ReleaseChannelNightly = "nightly"
+)
+
+// releaseChannels lists the channels in order of decreasing stability
+var releaseChannels = []string{ReleaseChannelStable, ReleaseChannelBeta, ReleaseChannelNightly}
+
+// Release sources
+const (
+	ReleaseSourceGitHub    = "github"
+	ReleaseSourceManifest  = "manifest"
+	ReleaseSourceDirectory = "directory"
+)
+
+// manifestFileName is the manifest read from a release directory
+const manifestFileName = "manifest.json"
+
+// manifestURLSchemes are the schemes a manifest fetched from a URL and its
+// release and asset URLs may use. Plain http and local files are rejected
+// so a manifest cannot point the updater at a tampered or local binary.
+var manifestURLSchemes = []string{"https"}
+
+// directoryURLSchemes are the schemes a manifest in a release directory
+// may use: its own files, or https downloads
+var directoryURLSchemes = []string{"file", "https"}
+
+// Release is a published version of the app, independent of where it is
+// listed
+type Release struct {
+	Version string `json:"version"`
+	URL     string `json:"url,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+	// Channel is stable, beta or nightly. When empty it is derived from
+	// the version: "-nightly..." pre-releases are nightly, other
+	// pre-releases beta.
+	Channel string         `json:"channel,omitempty"`
+	Assets  []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset is a downloadable file of a release. OS, Arch and Format
+// are optional in manifests and otherwise read from the name.
+type ReleaseAsset struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	OS     string `json:"os,omitempty"`
+	Arch   string `json:"arch,omitempty"`
+	Format string `json:"format,omitempty"`
+}
+
+// ReleaseManifest is the JSON document listing releases for the manifest
+// and directory sources. Relative URLs are resolved against the manifest.
+type ReleaseManifest struct {
+	Releases []Release `json:"releases"`
+}
+
+// releaseSource lists the releases available for update
+type releaseSource interface {
+	Releases() ([]Release, error)
+}
+
+// githubReleaseSource lists releases through the GitHub REST API
+type githubReleaseSource struct {
+	client     *http.Client
+	apiURL     string
+	repository string
+	token      string
+}
+
+// manifestReleaseSource reads a release manifest from a URL
+type manifestReleaseSource struct {
+	client *http.Client
+	url    string
+}
+
+// directoryReleaseSource reads the manifest in a local directory, for
+// installs without internet access
+type directoryReleaseSource struct {
+	dir string
+}
+
+// releaseSource returns the source configured in config.Updates
+func (a *App) releaseSource(config *AppConfig, client *http.Client) (releaseSource, error) {
+	updates := config.Updates
+
+	switch updates.Source {
+	case ReleaseSourceGitHub, "":
+		// An optional token raises the GitHub API rate limit and allows private repos
+		token, err := a.resolveSecret(config.GitHubToken)
+		if err != nil {
+			return nil, err
+		}
+		source := &githubReleaseSource{
+			client:     client,
+			apiURL:     strings.TrimSuffix(updates.APIURL, "/"),
+			repository: updates.Repository,
+			token:      token,
+		}
+		if source.apiURL == "" {
+			source.apiURL = defaultGitHubAPIURL
+		}
+		if source.repository == "" {
+			source.repository = GitHubRepo
+		}
+		return source, nil
+	case ReleaseSourceManifest:
+		return &manifestReleaseSource{client: client, url: updates.ManifestURL}, nil
+	case ReleaseSourceDirectory:
+		return &directoryReleaseSource{dir: updates.Directory}, nil
+	default:
+		return nil, fmt.Errorf("unsupported update source: %q", updates.Source)
+	}
+}
+
+// Releases lists the recent non-draft releases. /releases/latest never
+// returns pre-releases, so the list is fetched instead.
+func (s *githubReleaseSource) Releases() ([]Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases?per_page=30", s.apiURL, s.repository)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var githubReleases []GitHubRelease
+	err = json.Unmarshal(body, &githubReleases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+
+	var releases []Release
+	for _, gh := range githubReleases {
+		if gh.Draft {
+			continue
+		}
+		release := Release{Version: gh.TagName, URL: gh.HTMLURL, Notes: gh.Body}
+		// Releases marked as pre-release on GitHub are at most beta
+		if gh.Prerelease && releaseChannel(release) == ReleaseChannelStable {
+			release.Channel = ReleaseChannelBeta
+		}
+		for _, asset := range gh.Assets {
+			release.Assets = append(release.Assets, ReleaseAsset{Name: asset.Name, URL: asset.BrowserDownloadURL})
+		}
+		releases = append(releases, release)
+	}
+
+	return releases, nil
+}
+
+// Releases downloads and parses the manifest
+func (s *manifestReleaseSource) Releases() ([]Release, error) {
+	base, err := url.Parse(s.url)
+	if err != nil || !containsString(manifestURLSchemes, base.Scheme) || base.Host == "" {
+		return nil, fmt.Errorf("manifest URL must be an https URL: %q", s.url)
+	}
+
+	body, _, err := fetchURL(s.client, s.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release manifest: %w", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release manifest: %w", err)
+	}
+
+	return parseReleaseManifest(data, base, manifestURLSchemes)
+}
+
+// Releases reads manifest.json from the directory
+func (s *directoryReleaseSource) Releases() ([]Release, error) {
+	dir, err := filepath.Abs(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, manifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release manifest: %w", err)
+	}
+
+	base, err := url.Parse(fileURL(dir) + "/")
+	if err != nil {
+		return nil, err
+	}
+	return parseReleaseManifest(data, base, directoryURLSchemes)
+}
+
+// parseReleaseManifest parses a manifest and resolves its URLs against
+// base. Resolved URLs must use one of schemes.
+func parseReleaseManifest(data []byte, base *url.URL, schemes []string) ([]Release, error) {
+	var manifest ReleaseManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse release manifest: %w", err)
+	}
+
+	for i := range manifest.Releases {
+		release := &manifest.Releases[i]
+		if release.URL != "" {
+			resolved, err := resolveURL(base, release.URL, schemes)
+			if err != nil {
+				return nil, err
+			}
+			release.URL = resolved
+		}
+		for j := range release.Assets {
+			resolved, err := resolveURL(base, release.Assets[j].URL, schemes)
+			if err != nil {
+				return nil, err
+			}
+			release.Assets[j].URL = resolved
+		}
+	}
+
+	return manifest.Releases, nil
+}
+
+// resolveURL resolves a possibly relative reference against base and checks
+// that the result uses one of schemes
+func resolveURL(base *url.URL, ref string, schemes []string) (string, error) {
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL in release manifest: %q", ref)
+	}
+
+	resolved := base.ResolveReference(parsed)
+	if !containsString(schemes, resolved.Scheme) {
+		return "", fmt.Errorf("URL in release manifest must use %s: %q", strings.Join(schemes, " or "), resolved)
+	}
+	return resolved.String(), nil
+}
+
+// releaseChannel returns the channel a release belongs to
+func releaseChannel(release Release) string {
+	if release.Channel != "" {
+		return release.Channel
+	}
+
+	version, err := ParseVersion(release.Version)
+	switch {
+	case err != nil || !version.IsPrerelease():
+		return ReleaseChannelStable
+	case version.Prerelease[0] == ReleaseChannelNightly:
+		return ReleaseChannelNightly
+	default:
+		return ReleaseChannelBeta
+	}
+}
+
+// channelIncludes reports whether subscribers of channel are offered
+// releases of releaseChannel. Each channel also receives the more stable
+// ones, so beta users get stable releases too.
+func channelIncludes(channel, releaseChannel string) bool {
+	for _, c := range releaseChannels {
+		if c == releaseChannel {
+			return true
+		}
+		if c == channel {
+			return false
+		}
+	}
+	return false
+}
+
+// fetchURL opens an http(s) or file URL and returns its contents and size,
+// or -1 when the size is unknown
+func fetchURL(client *http.Client, rawURL string) (io.ReadCloser, int64, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if parsed.Scheme == "file" {
+		file, err := os.Open(fileURLPath(parsed))
+		if err != nil {
+			return nil, 0, err
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, 0, err
+		}
+		return file, info.Size(), nil
+	}
+
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+// fileURL converts an absolute path to a file URL
+func fileURL(path string) string {
+	path = filepath.ToSlash(path)
+	// Windows paths such as C:/dir need a leading slash
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// fileURLPath converts a file URL back to a local path
+func fileURLPath(u *url.URL) string {
+	path := u.Path
+	if runtime.GOOS == "windows" && len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseReleaseManifestSchemes(t *testing.T) {
+	httpsBase, _ := url.Parse("https://updates.example.com/weather/manifest.json")
+	fileBase, _ := url.Parse("file:///srv/releases/")
+
+	tests := []struct {
+		name     string
+		base     *url.URL
+		schemes  []string
+		assetURL string
+		want     string
+	}{
+		{"relative https", httpsBase, manifestURLSchemes, "v1.2.0/app.zip", "https://updates.example.com/weather/v1.2.0/app.zip"},
+		{"absolute https", httpsBase, manifestURLSchemes, "https://cdn.example.com/app.zip", "https://cdn.example.com/app.zip"},
+		{"http", httpsBase, manifestURLSchemes, "http://cdn.example.com/app.zip", ""},
+		{"file", httpsBase, manifestURLSchemes, "file:///tmp/app.zip", ""},
+		{"scheme relative file", httpsBase, manifestURLSchemes, "file:app.zip", ""},
+		{"directory file", fileBase, directoryURLSchemes, "app.zip", "file:///srv/releases/app.zip"},
+		{"directory https", fileBase, directoryURLSchemes, "https://cdn.example.com/app.zip", "https://cdn.example.com/app.zip"},
+		{"directory http", fileBase, directoryURLSchemes, "http://cdn.example.com/app.zip", ""},
+	}
+
+	for _, tt := range tests {
+		manifest := `{"releases": [{"version": "v1.2.0", "assets": [{"name": "app.zip", "url": "` + tt.assetURL + `"}]}]}`
+		releases, err := parseReleaseManifest([]byte(manifest), tt.base, tt.schemes)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%s: accepted %q", tt.name, tt.assetURL)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := releases[0].Assets[0].URL; got != tt.want {
+			t.Errorf("%s: URL = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestManifestReleaseSourceRequiresHTTPS(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"releases": [{"version": "v1.2.0", "assets": [{"name": "app.zip", "url": "app.zip"}]}]}`))
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+	source := &manifestReleaseSource{client: server.Client(), url: server.URL + "/manifest.json"}
+	releases, err := source.Releases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := server.URL + "/app.zip"; releases[0].Assets[0].URL != want {
+		t.Errorf("asset URL = %q, want %q", releases[0].Assets[0].URL, want)
+	}
+
+	plain := httptest.NewServer(handler)
+	defer plain.Close()
+	source = &manifestReleaseSource{client: plain.Client(), url: plain.URL + "/manifest.json"}
+	if _, err := source.Releases(); err == nil {
+		t.Error("manifest fetched over http was accepted")
+	}
+}
+
+func TestValidateUpdateConfigManifestURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://updates.example.com/manifest.json", true},
+		{"http://updates.example.com/manifest.json", false},
+		{"file:///srv/releases/manifest.json", false},
+		{"updates.example.com/manifest.json", false},
+	}
+
+	for _, tt := range tests {
+		config := UpdateConfig{Source: ReleaseSourceManifest, ManifestURL: tt.url}
+		if err := validateUpdateConfig(&config); (err == nil) != tt.valid {
+			t.Errorf("validateUpdateConfig(%q) = %v, want valid %v", tt.url, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// Secret storage backends accepted by AppConfig.SecretsBackend
+const (
+	SecretsBackendAuto    = "auto"
+	SecretsBackendKeyring = "keyring"
+	SecretsBackendFile    = "file"
+)
+
+// secretsService is the service name secrets are stored under in the OS keyring
+const secretsService = "myWeatherApp"
+
+// secretsPassphraseEnv names the environment variable that supplies the
+// passphrase for the encrypted secrets file
+const secretsPassphraseEnv = "MYWEATHERAPP_SECRETS_PASSPHRASE"
+
+// secretsKDFIterations is the PBKDF2 iteration count for the secrets file
+const secretsKDFIterations = 600000
+
+// ErrSecretNotFound is returned when a named secret does not exist
+var ErrSecretNotFound = errors.New("secret not found")
+
+// secretNamePattern restricts secret names to simple identifiers
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// SecretRef references a secret by name. Config fields hold a SecretRef
+// instead of the secret value so config.json never contains credentials.
+type SecretRef string
+
+// SecretStore stores named secrets
+type SecretStore interface {
+	// Name identifies the backend, e.g. "keyring" or "file"
+	Name() string
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+}
+
+// secretStore returns the store selected by the configuration. In auto mode
+// the OS keyring is used when available, otherwise the encrypted file.
+func (a *App) secretStore() (SecretStore, error) {
+	config, err := a.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	configPath, err := a.GetConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	fileStore := newFileSecretStore(filepath.Dir(configPath))
+
+	switch config.SecretsBackend {
+	case SecretsBackendFile:
+		return fileStore, nil
+	case SecretsBackendKeyring:
+		keyring := newKeyringSecretStore()
+		if keyring == nil {
+			return nil, fmt.Errorf("no OS keyring is available on this system")
+		}
+		return keyring, nil
+	case SecretsBackendAuto, "":
+		if keyring := newKeyringSecretStore(); keyring != nil {
+			return &autoSecretStore{keyring: keyring, file: fileStore}, nil
+		}
+		return fileStore, nil
+	default:
+		return nil, fmt.Errorf("unknown secrets backend: %s", config.SecretsBackend)
+	}
+}
+
+// resolveSecret returns the value a SecretRef points to. An empty reference
+// resolves to an empty value.
+func (a *App) resolveSecret(ref SecretRef) (string, error) {
+	if ref == "" {
+		return "", nil
+	}
+
+	store, err := a.secretStore()
+	if err != nil {
+		return "", err
+	}
+
+	value, err := store.Get(string(ref))
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s: %w", ref, err)
+	}
+
+	return value, nil
+}
+
+// SetSecret stores a secret under the given name
+func (a *App) SetSecret(name, value string) error {
+	if !secretNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name: %q", name)
+	}
+
+	store, err := a.secretStore()
+	if err != nil {
+		return err
+	}
+
+	return store.Set(name, value)
+}
+
+// DeleteSecret removes a stored secret
+func (a *App) DeleteSecret(name string) error {
+	if !secretNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name: %q", name)
+	}
+
+	store, err := a.secretStore()
+	if err != nil {
+		return err
+	}
+
+	return store.Delete(name)
+}
+
+// HasSecret reports whether a secret with the given name is stored. The
+// value itself is never returned to the frontend.
+func (a *App) HasSecret(name string) (bool, error) {
+	store, err := a.secretStore()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = store.Get(name)
+	if errors.Is(err, ErrSecretNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// GetSecretsBackend returns the name of the secret storage backend in use
+func (a *App) GetSecretsBackend() (string, error) {
+	store, err := a.secretStore()
+	if err != nil {
+		return "", err
+	}
+
+	return store.Name(), nil
+}
+
+// autoSecretStore uses the OS keyring and falls back to the file store when
+// the keyring fails at runtime, e.g. when secret-tool is installed but no
+// Secret Service daemon is running
+type autoSecretStore struct {
+	keyring SecretStore
+	file    SecretStore
+}
+
+// secretsProbeName is looked up to check that the keyring responds
+const secretsProbeName = "myWeatherApp.probe"
+
+// Name reports the backend that is currently in use
+func (s *autoSecretStore) Name() string {
+	if _, err := s.keyring.Get(secretsProbeName); err != nil && !errors.Is(err, ErrSecretNotFound) {
+		return s.file.Name()
+	}
+	return s.keyring.Name()
+}
+
+// Get reads from the keyring, then from the file, which holds secrets saved
+// while the keyring was unavailable
+func (s *autoSecretStore) Get(name string) (string, error) {
+	value, err := s.keyring.Get(name)
+	if err == nil {
+		return value, nil
+	}
+	if !errors.Is(err, ErrSecretNotFound) {
+		log.Printf("OS keyring unavailable, reading secret %s from the secrets file: %v", name, err)
+	}
+	return s.file.Get(name)
+}
+
+func (s *autoSecretStore) Set(name, value string) error {
+	err := s.keyring.Set(name, value)
+	if err == nil {
+		return nil
+	}
+	log.Printf("OS keyring unavailable, saving secret %s to the secrets file: %v", name, err)
+	return s.file.Set(name, value)
+}
+
+// Delete removes the secret from both stores
+func (s *autoSecretStore) Delete(name string) error {
+	keyringErr := s.keyring.Delete(name)
+	fileErr := s.file.Delete(name)
+	if keyringErr == nil || fileErr == nil {
+		return nil
+	}
+	if !errors.Is(keyringErr, ErrSecretNotFound) {
+		return keyringErr
+	}
+	return fileErr
+}
+
+// fileSecretStore keeps secrets in an AES-GCM encrypted file. The key is
+// derived from the MYWEATHERAPP_SECRETS_PASSPHRASE environment variable, or
+// from a random passphrase kept in a user-only key file next to it. Without
+// the environment variable anyone who can read both files can decrypt the
+// secrets, so the key file only guards against the secrets file being
+// copied or shared on its own; file permissions are the real protection.
+type fileSecretStore struct {
+	mu      sync.Mutex
+	path    string
+	keyPath string
+}
+
+// secretsFile is the on-disk layout of the encrypted secrets file
+type secretsFile struct {
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// newFileSecretStore creates a file store in the given directory
+func newFileSecretStore(dir string) *fileSecretStore {
+	return &fileSecretStore{
+		path:    filepath.Join(dir, "secrets.enc"),
+		keyPath: filepath.Join(dir, "secrets.key"),
+	}
+}
+
+func (s *fileSecretStore) Name() string {
+	return SecretsBackendFile
+}
+
+func (s *fileSecretStore) Get(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secrets[name]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+func (s *fileSecretStore) Set(name, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	secrets[name] = value
+	return s.save(secrets)
+}
+
+func (s *fileSecretStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := secrets[name]; !ok {
+		return ErrSecretNotFound
+	}
+	delete(secrets, name)
+	return s.save(secrets)
+}
+
+// passphrase returns the passphrase used to derive the file key, creating
+// a random one on first use
+func (s *fileSecretStore) passphrase() (string, error) {
+	if passphrase := os.Getenv(secretsPassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	data, err := os.ReadFile(s.keyPath)
+	if err == nil {
+		return string(data), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	passphrase := hex.EncodeToString(random)
+	if err := os.WriteFile(s.keyPath, []byte(passphrase), 0600); err != nil {
+		return "", fmt.Errorf("failed to create secrets key: %w", err)
+	}
+	log.Printf("Secrets are encrypted with a key stored in %s; set %s to protect them with a passphrase", s.keyPath, secretsPassphraseEnv)
+
+	return passphrase, nil
+}
+
+// cipher derives the AES-GCM cipher for the given salt
+func (s *fileSecretStore) cipher(salt []byte) (cipher.AEAD, error) {
+	passphrase, err := s.passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, secretsKDFIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// load decrypts the secrets file. A missing file holds no secrets.
+func (s *fileSecretStore) load() (map[string]string, error) {
+	secrets := make(map[string]string)
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file secretsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file: %w", err)
+	}
+
+	salt, err := hex.DecodeString(file.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt secrets file: %w", err)
+	}
+	nonce, err := hex.DecodeString(file.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt secrets file: %w", err)
+	}
+	ciphertext, err := hex.DecodeString(file.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt secrets file: %w", err)
+	}
+
+	aead, err := s.cipher(salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("corrupt secrets file: bad nonce")
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secrets file (wrong passphrase?)")
+	}
+
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// save encrypts the secrets with a fresh salt and nonce
+func (s *fileSecretStore) save(secrets map[string]string) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	aead, err := s.cipher(salt)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(secretsFile{
+		Salt:       hex.EncodeToString(salt),
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(aead.Seal(nil, nonce, plaintext, nil)),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0600)
+}
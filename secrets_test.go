@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFileSecretStoreRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store := newFileSecretStore(dir)
+
+	if _, err := store.Get("token"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("Get on an empty store = %v, want ErrSecretNotFound", err)
+	}
+	if err := store.Set("token", "s3cret & \"quoted\""); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new store reads the same files
+	store = newFileSecretStore(dir)
+	value, err := store.Get("token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "s3cret & \"quoted\"" {
+		t.Errorf("Get = %q", value)
+	}
+
+	data, err := os.ReadFile(store.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Error("secrets file contains the plain text value")
+	}
+
+	if err := store.Delete("token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("token"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Delete of a missing secret = %v, want ErrSecretNotFound", err)
+	}
+}
+
+func TestFileSecretStoreWrongPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(secretsPassphraseEnv, "correct horse")
+	if err := newFileSecretStore(dir).Set("token", "value"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(secretsPassphraseEnv, "battery staple")
+	_, err := newFileSecretStore(dir).Get("token")
+	if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("Get with the wrong passphrase = %v, want a decryption error", err)
+	}
+}
+
+func TestFileSecretStoreCorruptFile(t *testing.T) {
+	tests := map[string]string{
+		"not json":       "{",
+		"bad hex":        `{"salt": "zz", "nonce": "00", "ciphertext": "00"}`,
+		"bad nonce":      `{"salt": "00", "nonce": "00", "ciphertext": "00"}`,
+		"bad ciphertext": `{"salt": "00", "nonce": "000000000000000000000000", "ciphertext": "00"}`,
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := newFileSecretStore(t.TempDir())
+			if err := os.WriteFile(store.path, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.Get("token"); err == nil || errors.Is(err, ErrSecretNotFound) {
+				t.Errorf("Get = %v, want an error", err)
+			}
+		})
+	}
+}
+
+// brokenKeyring fails like secret-tool without a Secret Service daemon
+type brokenKeyring struct{}
+
+var errNoDaemon = errors.New("cannot autolaunch D-Bus without X11")
+
+func (brokenKeyring) Name() string                    { return SecretsBackendKeyring }
+func (brokenKeyring) Get(name string) (string, error) { return "", errNoDaemon }
+func (brokenKeyring) Set(name, value string) error    { return errNoDaemon }
+func (brokenKeyring) Delete(name string) error        { return errNoDaemon }
+
+func TestAutoSecretStoreFallsBackToFile(t *testing.T) {
+	store := &autoSecretStore{keyring: brokenKeyring{}, file: newFileSecretStore(t.TempDir())}
+
+	if name := store.Name(); name != SecretsBackendFile {
+		t.Errorf("Name = %q, want %q", name, SecretsBackendFile)
+	}
+	if err := store.Set("token", "value"); err != nil {
+		t.Fatalf("Set = %v, want fallback to the file", err)
+	}
+	value, err := store.Get("token")
+	if err != nil || value != "value" {
+		t.Fatalf("Get = %q, %v", value, err)
+	}
+	if err := store.Delete("token"); err != nil {
+		t.Fatalf("Delete = %v", err)
+	}
+	if _, err := store.Get("token"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrSecretNotFound", err)
+	}
+}
+
+func TestDeleteSecretValidatesName(t *testing.T) {
+	setTestHome(t)
+
+	if err := (&App{}).DeleteSecret("../config"); err == nil {
+		t.Error("DeleteSecret accepted an invalid name")
+	}
+}
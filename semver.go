@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed Semantic Versioning 2.0.0 version
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease []string // dot-separated identifiers after "-"
+	Build      []string // dot-separated identifiers after "+"
+}
+
+// ParseVersion parses a SemVer 2.0.0 version with an optional "v" prefix,
+// e.g. "v1.2.3-beta.1+build.5"
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	text := strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+
+	text, build, hasBuild := strings.Cut(text, "+")
+	if hasBuild {
+		identifiers, err := parseIdentifiers(build, false)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid build metadata in %q: %w", s, err)
+		}
+		v.Build = identifiers
+	}
+
+	text, prerelease, hasPrerelease := strings.Cut(text, "-")
+	if hasPrerelease {
+		identifiers, err := parseIdentifiers(prerelease, true)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid pre-release in %q: %w", s, err)
+		}
+		v.Prerelease = identifiers
+	}
+
+	parts := strings.Split(text, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: expected MAJOR.MINOR.PATCH", s)
+	}
+	numbers := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		if !isNumeric(part) || len(part) > 1 && part[0] == '0' {
+			return Version{}, fmt.Errorf("invalid version %q: %q is not a number without leading zeros", s, part)
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		*numbers[i] = n
+	}
+
+	return v, nil
+}
+
+// parseIdentifiers splits and checks dot-separated identifiers. Numeric
+// pre-release identifiers must not have leading zeros.
+func parseIdentifiers(s string, prerelease bool) ([]string, error) {
+	identifiers := strings.Split(s, ".")
+	for _, id := range identifiers {
+		if id == "" {
+			return nil, fmt.Errorf("empty identifier")
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r == '-') {
+				return nil, fmt.Errorf("invalid character %q in %q", r, id)
+			}
+		}
+		if prerelease && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return nil, fmt.Errorf("numeric identifier %q has a leading zero", id)
+		}
+	}
+	return identifiers, nil
+}
+
+// isNumeric reports whether s is a non-empty string of ASCII digits
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPrerelease reports whether v has pre-release identifiers
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// String formats v without a "v" prefix
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as v is lower than, equal to or higher than
+// other in SemVer precedence. Build metadata is ignored.
+func (v Version) Compare(other Version) int {
+	if c := compareUint(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+
+	// A version without pre-release identifiers has higher precedence
+	switch {
+	case len(v.Prerelease) == 0 && len(other.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(other.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(other.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], other.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	// A larger set of identifiers has higher precedence
+	return compareUint(uint64(len(v.Prerelease)), uint64(len(other.Prerelease)))
+}
+
+// compareIdentifier compares pre-release identifiers: numerically when both
+// are numeric, numeric below alphanumeric, otherwise in ASCII order
+func compareIdentifier(a, b string) int {
+	aNumeric, bNumeric := isNumeric(a), isNumeric(b)
+	switch {
+	case aNumeric && bNumeric:
+		// Without leading zeros, a longer number is larger
+		if c := compareUint(uint64(len(a)), uint64(len(b))); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// compareUint returns -1, 0 or 1 as a is less than, equal to or greater than b
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		valid bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"v1.2.3", "1.2.3", true},
+		{"V1.2.3", "1.2.3", true},
+		{"1.0.0-alpha.1", "1.0.0-alpha.1", true},
+		{"1.0.0-x-y.0a", "1.0.0-x-y.0a", true},
+		{"1.0.0+build.007", "1.0.0+build.007", true},
+		{"1.0.0-rc.1+sha.abc123", "1.0.0-rc.1+sha.abc123", true},
+		{"01.2.3", "", false},
+		{"1.02.3", "", false},
+		{"1.2.03", "", false},
+		{"1.0.0-01", "", false},
+		{"1.2", "", false},
+		{"1.2.3.4", "", false},
+		{"vv1.2.3", "", false},
+		{"1.2.x", "", false},
+		{"1.0.0-", "", false},
+		{"1.0.0-alpha..1", "", false},
+		{"1.0.0+", "", false},
+		{"1.0.0-beta_1", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		v, err := ParseVersion(tt.input)
+		if (err == nil) != tt.valid {
+			t.Errorf("ParseVersion(%q) error = %v, want valid %v", tt.input, err, tt.valid)
+			continue
+		}
+		if tt.valid && v.String() != tt.want {
+			t.Errorf("ParseVersion(%q) = %q, want %q", tt.input, v.String(), tt.want)
+		}
+	}
+}
+
+func TestVersionPrecedence(t *testing.T) {
+	// Each version has lower precedence than the next, as in the example
+	// of the SemVer 2.0.0 specification
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"1.9.0",
+		"1.10.0",
+		"2.0.0",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			a, err := ParseVersion(ordered[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := ParseVersion(ordered[j])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := compareUint(uint64(i), uint64(j))
+			if got := a.Compare(b); got != want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+}
+
+func TestVersionCompareIgnoresBuildAndPrefix(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"1.0.0+build.1", "1.0.0+build.2"},
+		{"1.0.0+build.1", "1.0.0"},
+		{"1.0.0-rc.1+a", "1.0.0-rc.1+b"},
+		{"v1.2.3", "1.2.3"},
+		{"v1.2.3-beta.1", "1.2.3-beta.1+meta"},
+	}
+
+	for _, tt := range tests {
+		a, err := ParseVersion(tt.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseVersion(tt.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := a.Compare(b); c != 0 {
+			t.Errorf("%s.Compare(%s) = %d, want 0", tt.a, tt.b, c)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// updatePublicKey is the minisign public key release checksums are signed
+// with, in the base64 form printed by "minisign -G". It is set at build time:
+//
+//	go build -ldflags "-X main.updatePublicKey=RWQ..."
+//
+// Builds without a key cannot verify releases and refuse to install updates.
+var updatePublicKey string
+
+// Minisign signature algorithms
+const (
+	minisignAlgorithmLegacy    = "Ed" // signature over the file contents
+	minisignAlgorithmPrehashed = "ED" // signature over the BLAKE2b-512 hash
+)
+
+// signatureExtension is appended to the checksums file name to find its
+// detached signature
+const signatureExtension = ".minisig"
+
+// minisignPublicKey is a parsed minisign public key
+type minisignPublicKey struct {
+	keyID [8]byte
+	key   ed25519.PublicKey
+}
+
+// minisignSignature is a parsed minisign signature file
+type minisignSignature struct {
+	algorithm       string
+	keyID           [8]byte
+	signature       []byte
+	trustedComment  string
+	globalSignature []byte
+}
+
+// parseMinisignPublicKey parses a public key, either the bare base64 line
+// or the whole minisign.pub file
+func parseMinisignPublicKey(text string) (*minisignPublicKey, error) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	encoded := strings.TrimSpace(lines[len(lines)-1])
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(data) != 2+8+ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid minisign public key")
+	}
+	if string(data[:2]) != minisignAlgorithmLegacy {
+		return nil, fmt.Errorf("unsupported public key algorithm %q", data[:2])
+	}
+
+	key := &minisignPublicKey{key: ed25519.PublicKey(data[10:])}
+	copy(key.keyID[:], data[2:10])
+	return key, nil
+}
+
+// parseMinisignSignature parses a .minisig file
+func parseMinisignSignature(data []byte) (*minisignSignature, error) {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n")), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "untrusted comment:") {
+		return nil, fmt.Errorf("invalid minisign signature file")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return nil, fmt.Errorf("invalid minisign signature")
+	}
+
+	trustedComment, ok := strings.CutPrefix(lines[2], "trusted comment: ")
+	if !ok {
+		return nil, fmt.Errorf("invalid minisign trusted comment")
+	}
+
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("invalid minisign global signature")
+	}
+
+	signature := &minisignSignature{
+		algorithm:       string(sig[:2]),
+		signature:       sig[10:],
+		trustedComment:  trustedComment,
+		globalSignature: global,
+	}
+	copy(signature.keyID[:], sig[2:10])
+	return signature, nil
+}
+
+// verifyMinisign checks that signature was made over message by key,
+// including the trusted comment
+func verifyMinisign(key *minisignPublicKey, message []byte, signature *minisignSignature) error {
+	if signature.keyID != key.keyID {
+		// minisign shows key IDs as little-endian numbers
+		return fmt.Errorf("signed with key %016X, expected key %016X",
+			binary.LittleEndian.Uint64(signature.keyID[:]), binary.LittleEndian.Uint64(key.keyID[:]))
+	}
+
+	signed := message
+	switch signature.algorithm {
+	case minisignAlgorithmLegacy:
+	case minisignAlgorithmPrehashed:
+		hash := blake2b.Sum512(message)
+		signed = hash[:]
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", signature.algorithm)
+	}
+
+	if !ed25519.Verify(key.key, signed, signature.signature) {
+		return fmt.Errorf("signature does not match")
+	}
+
+	// The global signature covers the trusted comment, so it cannot be
+	// swapped between releases
+	global := append(bytes.Clone(signature.signature), signature.trustedComment...)
+	if !ed25519.Verify(key.key, global, signature.globalSignature) {
+		return fmt.Errorf("trusted comment signature does not match")
+	}
+
+	return nil
+}
+
+// verifyChecksumsSignature verifies a checksums file against its detached
+// signature using the embedded public key. The trusted comment must name
+// version, so checksums signed for an older release cannot be replayed to
+// downgrade the app.
+func verifyChecksumsSignature(checksums, signature []byte, version string) error {
+	if updatePublicKey == "" {
+		return fmt.Errorf("this build has no update signing key, so releases cannot be verified")
+	}
+
+	key, err := parseMinisignPublicKey(updatePublicKey)
+	if err != nil {
+		return fmt.Errorf("embedded update signing key: %w", err)
+	}
+
+	sig, err := parseMinisignSignature(signature)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyMinisign(key, checksums, sig); err != nil {
+		return fmt.Errorf("release checksums signature: %w", err)
+	}
+
+	signed, ok := trustedCommentVersion(sig.trustedComment)
+	if !ok {
+		return fmt.Errorf("release checksums signature does not name a version")
+	}
+	if !sameVersion(signed, version) {
+		return fmt.Errorf("release checksums are signed for %s, not %s", signed, version)
+	}
+	return nil
+}
+
+// trustedCommentVersion returns the version field of a trusted comment
+// such as "timestamp:1760000000 file:SHA256SUMS version:v1.2.0"
+func trustedCommentVersion(comment string) (string, bool) {
+	for _, field := range strings.Fields(comment) {
+		if version, ok := strings.CutPrefix(field, "version:"); ok && version != "" {
+			return version, true
+		}
+	}
+	return "", false
+}
+
+// sameVersion reports whether a and b name the same version, ignoring
+// build metadata and a "v" prefix
+func sameVersion(a, b string) bool {
+	va, errA := ParseVersion(a)
+	vb, errB := ParseVersion(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return va.Compare(vb) == 0
+}
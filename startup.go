@@ -2,189 +2,195 @@ package main
 
 import (
 	"fmt"
-	"golang.org/x/sys/windows/registry"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 )
 
-// EnableStartup enables the app to launch on system startup
-func (a *App) EnableStartup() error {
-	switch runtime.GOOS {
-	case "windows":
-		return a.enableStartupWindows()
-	case "darwin":
-		return a.enableStartupMacOS()
-	case "linux":
-		return a.enableStartupLinux()
-	default:
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
-	}
+// startupAppName identifies the app's startup entry on every platform
+const startupAppName = "myWeatherApp"
+
+// Startup mechanisms reported in StartupStatus
+const (
+	StartupMechanismRegistry    = "registry"
+	StartupMechanismLaunchAgent = "launchAgent"
+	StartupMechanismXDG         = "xdg"
+	StartupMechanismSystemd     = "systemd"
+)
+
+// maxStartupDelay limits the delayed start, in seconds
+const maxStartupDelay = 600
+
+// StartupConfig controls how the app is launched at login. Changes apply
+// the next time startup is enabled.
+type StartupConfig struct {
+	// Mechanism is the Linux autostart method: xdg for a desktop entry in
+	// ~/.config/autostart, or systemd for a user service that is restarted
+	// if it crashes. Other platforms have a single mechanism.
+	Mechanism string `json:"mechanism"`
+	// StartMinimized starts with only the tray icon
+	StartMinimized bool `json:"startMinimized"`
+	// Delay waits this many seconds after login before starting
+	Delay int `json:"delay"`
+	// Args are extra arguments, e.g. ["--profile", "work"]
+	Args []string `json:"args,omitempty"`
+	// RepairStale re-registers an entry whose executable no longer exists
+	// for the running executable at launch
+	RepairStale bool `json:"repairStale"`
 }
 
-// DisableStartup disables the app from launching on system startup
-func (a *App) DisableStartup() error {
-	switch runtime.GOOS {
-	case "windows":
-		return a.disableStartupWindows()
-	case "darwin":
-		return a.disableStartupMacOS()
-	case "linux":
-		return a.disableStartupLinux()
-	default:
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
-	}
+// StartupEntry is the command registered to run at login
+type StartupEntry struct {
+	ExePath string
+	Args    []string
 }
 
-// IsStartupEnabled checks if startup is enabled
-func (a *App) IsStartupEnabled() (bool, error) {
-	switch runtime.GOOS {
-	case "windows":
-		return a.isStartupEnabledWindows()
-	case "darwin":
-		return a.isStartupEnabledMacOS()
-	case "linux":
-		return a.isStartupEnabledLinux()
-	default:
-		return false, fmt.Errorf("unsupported platform: %s", runtime.GOOS)
-	}
+// StartupStatus describes the registered startup entry
+type StartupStatus struct {
+	Enabled   bool     `json:"enabled"`
+	Mechanism string   `json:"mechanism,omitempty"`
+	ExePath   string   `json:"exePath,omitempty"`
+	Args      []string `json:"args,omitempty"`
+	// Current reports whether the entry starts the running executable
+	Current bool `json:"current"`
+	// Missing reports that the registered executable no longer exists
+	Missing bool `json:"missing"`
 }
 
-// Windows implementation
-func (a *App) enableStartupWindows() error {
-	exePath, err := os.Executable()
+// StartupManager registers the app to launch when the user logs in. Each
+// platform provides an implementation in a startup_<os>.go file.
+type StartupManager interface {
+	// Enable registers entry to run at login, replacing any previous entry
+	Enable(entry StartupEntry) error
+	// Disable removes the registration; it is not an error if there is none
+	Disable() error
+	// Status reads the registered entry
+	Status() (StartupStatus, error)
+}
+
+// EnableStartup enables the app to launch on system startup
+func (a *App) EnableStartup() error {
+	config, err := a.LoadConfig()
 	if err != nil {
 		return err
 	}
 
-	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Run`, registry.SET_VALUE)
+	manager, err := newStartupManager(&config.Startup)
 	if err != nil {
 		return err
 	}
-	defer key.Close()
-
-	return key.SetStringValue("myWeatherApp", exePath)
-}
 
-func (a *App) disableStartupWindows() error {
-	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Run`, registry.SET_VALUE)
+	exePath, err := installedExecutable()
 	if err != nil {
 		return err
 	}
-	defer key.Close()
 
-	return key.DeleteValue("myWeatherApp")
+	return manager.Enable(StartupEntry{ExePath: exePath, Args: startupArgs(&config.Startup)})
 }
 
-func (a *App) isStartupEnabledWindows() (bool, error) {
-	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Run`, registry.QUERY_VALUE)
+// DisableStartup disables the app from launching on system startup
+func (a *App) DisableStartup() error {
+	config, err := a.LoadConfig()
 	if err != nil {
-		return false, nil
+		return err
 	}
-	defer key.Close()
 
-	_, _, err = key.GetStringValue("myWeatherApp")
-	return err == nil, nil
-}
-
-// macOS implementation
-func (a *App) enableStartupMacOS() error {
-	homeDir, err := os.UserHomeDir()
+	manager, err := newStartupManager(&config.Startup)
 	if err != nil {
 		return err
 	}
 
-	launchAgentsDir := filepath.Join(homeDir, "Library/LaunchAgents")
-	if err := os.MkdirAll(launchAgentsDir, 0755); err != nil {
-		return err
-	}
+	return manager.Disable()
+}
 
-	plistPath := filepath.Join(launchAgentsDir, "com.myWeatherApp.plist")
-	exePath, err := os.Executable()
+// IsStartupEnabled reports whether startup is enabled, with which mechanism,
+// whether the entry starts the running executable and whether its
+// executable still exists
+func (a *App) IsStartupEnabled() (*StartupStatus, error) {
+	config, err := a.LoadConfig()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	plistContent := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
-<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
-<plist version="1.0">
-<dict>
-	<key>Label</key>
-	<string>com.myWeatherApp</string>
-	<key>ProgramArguments</key>
-	<array>
-		<string>%s</string>
-	</array>
-	<key>RunAtLoad</key>
-	<true/>
-</dict>
-</plist>`, exePath)
-
-	return os.WriteFile(plistPath, []byte(plistContent), 0644)
-}
-
-func (a *App) disableStartupMacOS() error {
-	homeDir, err := os.UserHomeDir()
+	manager, err := newStartupManager(&config.Startup)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	plistPath := filepath.Join(homeDir, "Library/LaunchAgents/com.myWeatherApp.plist")
-	return os.Remove(plistPath)
+
+	return startupStatus(manager)
 }
 
-func (a *App) isStartupEnabledMacOS() (bool, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return false, err
+// startupArgs returns the launch arguments for the startup options
+func startupArgs(config *StartupConfig) []string {
+	var args []string
+	if config.StartMinimized {
+		args = append(args, "--minimized")
+	}
+	if config.Delay > 0 {
+		args = append(args, "--launch-delay", strconv.Itoa(config.Delay))
 	}
-	plistPath := filepath.Join(homeDir, "Library/LaunchAgents/com.myWeatherApp.plist")
-	_, err = os.Stat(plistPath)
-	return err == nil, nil
+	return append(args, config.Args...)
 }
 
-// Linux implementation
-func (a *App) enableStartupLinux() error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
+// validateStartupConfig checks the startup options
+func validateStartupConfig(config *StartupConfig) error {
+	switch config.Mechanism {
+	case StartupMechanismXDG, StartupMechanismSystemd, "":
+	default:
+		return fmt.Errorf("unsupported startup.mechanism: %q", config.Mechanism)
 	}
 
-	autostartDir := filepath.Join(homeDir, ".config/autostart")
-	if err := os.MkdirAll(autostartDir, 0755); err != nil {
-		return err
+	if config.Delay < 0 || config.Delay > maxStartupDelay {
+		return fmt.Errorf("startup.delay must be between 0 and %d seconds", maxStartupDelay)
 	}
 
-	desktopPath := filepath.Join(autostartDir, "myWeatherApp.desktop")
-	exePath, err := os.Executable()
-	if err != nil {
-		return err
+	return nil
+}
+
+// parseStartupCommand splits a registered command line with split into the
+// executable and its arguments. Older entries may hold an unquoted path
+// with spaces, such as C:\Program Files\myWeatherApp\myWeatherApp.exe,
+// which splits into fragments of the path, so when the command does not
+// split into an existing executable the whole value is tried as the path.
+func parseStartupCommand(command string, split func(string) ([]string, error)) (string, []string) {
+	args, err := split(command)
+	if err == nil && len(args) > 0 && isFile(args[0]) {
+		return args[0], args[1:]
 	}
 
-	desktopContent := fmt.Sprintf(`[Desktop Entry]
-Type=Application
-Name=My Weather App 2
-Exec=%s
-Terminal=false
-X-GNOME-Autostart-enabled=true`, exePath)
+	whole := strings.Trim(strings.TrimSpace(command), `"`)
+	if err != nil || len(args) == 0 || isFile(whole) {
+		return whole, nil
+	}
+	return args[0], args[1:]
+}
 
-	return os.WriteFile(desktopPath, []byte(desktopContent), 0644)
+// validStartupArgs reports whether args are launch options the app accepts
+// without running a subcommand
+func validStartupArgs(args []string) bool {
+	options, err := ParseLaunchOptions(args, io.Discard)
+	return err == nil && len(options.Args) == 0
 }
 
-func (a *App) disableStartupLinux() error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
-	}
-	autostartPath := filepath.Join(homeDir, ".config/autostart/myWeatherApp.desktop")
-	return os.Remove(autostartPath)
+// isFile reports whether path exists and is not a directory
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
 }
 
-func (a *App) isStartupEnabledLinux() (bool, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return false, err
+// sameFile reports whether two paths name the same file, resolving symlinks
+func sameFile(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Clean(a), filepath.Clean(b))
 	}
-	autostartPath := filepath.Join(homeDir, ".config/autostart/myWeatherApp.desktop")
-	_, err = os.Stat(autostartPath)
-	return err == nil, nil
+	return filepath.Clean(a) == filepath.Clean(b)
 }
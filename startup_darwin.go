@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// launchAgentLabel is the launchd label of the app's login item
+const launchAgentLabel = "com.myWeatherApp"
+
+// launchAgentStartupManager registers the app as a launchd agent in
+// ~/Library/LaunchAgents
+type launchAgentStartupManager struct {
+	homeDir string
+}
+
+// newStartupManager returns the macOS startup manager for the current user
+func newStartupManager(config *StartupConfig) (StartupManager, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return newLaunchAgentStartupManager(homeDir), nil
+}
+
+// newLaunchAgentStartupManager returns a startup manager for the user whose
+// home directory is homeDir
+func newLaunchAgentStartupManager(homeDir string) *launchAgentStartupManager {
+	return &launchAgentStartupManager{homeDir: homeDir}
+}
+
+// plistPath returns the path of the launch agent property list
+func (m *launchAgentStartupManager) plistPath() string {
+	return filepath.Join(m.homeDir, "Library", "LaunchAgents", launchAgentLabel+".plist")
+}
+
+func (m *launchAgentStartupManager) Enable(entry StartupEntry) error {
+	if err := os.MkdirAll(filepath.Dir(m.plistPath()), 0755); err != nil {
+		return err
+	}
+
+	var arguments strings.Builder
+	for _, arg := range append([]string{entry.ExePath}, entry.Args...) {
+		var escaped bytes.Buffer
+		if err := xml.EscapeText(&escaped, []byte(arg)); err != nil {
+			return err
+		}
+		arguments.WriteString("\t\t<string>" + escaped.String() + "</string>\n")
+	}
+
+	plistContent := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>RunAtLoad</key>
+	<true/>
+</dict>
+</plist>`, launchAgentLabel, arguments.String())
+
+	return os.WriteFile(m.plistPath(), []byte(plistContent), 0644)
+}
+
+func (m *launchAgentStartupManager) Disable() error {
+	if err := os.Remove(m.plistPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (m *launchAgentStartupManager) Status() (StartupStatus, error) {
+	content, err := os.ReadFile(m.plistPath())
+	if os.IsNotExist(err) {
+		return StartupStatus{}, nil
+	}
+	if err != nil {
+		return StartupStatus{}, err
+	}
+
+	status := StartupStatus{Enabled: true, Mechanism: StartupMechanismLaunchAgent}
+	if args, err := plistProgramArguments(content); err == nil && len(args) > 0 {
+		status.ExePath = args[0]
+		status.Args = args[1:]
+	}
+	return status, nil
+}
+
+// plistProgramArguments reads the ProgramArguments array of a launch agent
+// property list
+func plistProgramArguments(content []byte) ([]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	// The DOCTYPE is not fetched or validated
+	decoder.Strict = false
+
+	var lastKey string
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no ProgramArguments in %s", launchAgentLabel)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "key":
+			if err := decoder.DecodeElement(&lastKey, &start); err != nil {
+				return nil, err
+			}
+		case "array":
+			if lastKey != "ProgramArguments" {
+				continue
+			}
+			var array struct {
+				Strings []string `xml:"string"`
+			}
+			if err := decoder.DecodeElement(&array, &start); err != nil {
+				return nil, err
+			}
+			return array.Strings, nil
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLaunchAgentStartupManager(t *testing.T) {
+	home := setTestHome(t)
+	manager, err := newStartupManager(&StartupConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plistPath := manager.(*launchAgentStartupManager).plistPath()
+	if !strings.HasPrefix(plistPath, home) {
+		t.Fatalf("plist path %s is outside the test home", plistPath)
+	}
+
+	if status, err := manager.Status(); err != nil || status.Enabled {
+		t.Fatalf("Status before Enable = %+v, %v", status, err)
+	}
+
+	exePath := filepath.Join(home, "Applications", "my Weather App.app", "Contents", "MacOS", "myWeatherApp")
+	args := []string{"--profile", "work & play", `--tray-label=<{temp}> "now"`, "100%", "$HOME", ""}
+	if err := manager.Enable(StartupEntry{ExePath: exePath, Args: args}); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := manager.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := StartupStatus{Enabled: true, Mechanism: StartupMechanismLaunchAgent, ExePath: exePath, Args: args}
+	if !reflect.DeepEqual(status, want) {
+		t.Fatalf("Status = %+v, want %+v", status, want)
+	}
+
+	checked, err := startupStatus(manager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !checked.Missing || checked.Current {
+		t.Errorf("startupStatus = %+v, want a missing executable", checked)
+	}
+
+	if err := manager.Disable(); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := manager.Status(); err != nil || status.Enabled {
+		t.Fatalf("Status after Disable = %+v, %v", status, err)
+	}
+	if _, err := os.Stat(plistPath); !os.IsNotExist(err) {
+		t.Errorf("launch agent not removed: %v", err)
+	}
+	// Disabling twice is not an error
+	if err := manager.Disable(); err != nil {
+		t.Errorf("second Disable = %v", err)
+	}
+}
+
+func TestPlistProgramArguments(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>com.myWeatherApp</string>
+	<key>LimitLoadToSessionType</key>
+	<array>
+		<string>Aqua</string>
+	</array>
+	<key>ProgramArguments</key>
+	<array>
+		<string>/Applications/myWeatherApp.app/Contents/MacOS/myWeatherApp</string>
+		<string>--minimized</string>
+	</array>
+</dict>
+</plist>`
+
+	args, err := plistProgramArguments([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/Applications/myWeatherApp.app/Contents/MacOS/myWeatherApp", "--minimized"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("plistProgramArguments = %q, want %q", args, want)
+	}
+
+	if _, err := plistProgramArguments([]byte(`<plist><dict></dict></plist>`)); err == nil {
+		t.Error("plist without ProgramArguments accepted")
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// linuxStartupManager enables the configured autostart mechanism and makes
+// sure the other one is removed, so the app is never started twice
+type linuxStartupManager struct {
+	mechanism string
+	xdg       *xdgAutostartManager
+	systemd   *systemdStartupManager
+}
+
+// newStartupManager returns the Linux startup manager for the current user
+func newStartupManager(config *StartupConfig) (StartupManager, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	mechanism := config.Mechanism
+	if mechanism == "" {
+		mechanism = StartupMechanismXDG
+	}
+
+	return &linuxStartupManager{
+		mechanism: mechanism,
+		xdg:       newXDGAutostartManager(homeDir),
+		systemd:   newSystemdStartupManager(homeDir),
+	}, nil
+}
+
+// managers returns the configured mechanism's manager first
+func (m *linuxStartupManager) managers() []StartupManager {
+	if m.mechanism == StartupMechanismSystemd {
+		return []StartupManager{m.systemd, m.xdg}
+	}
+	return []StartupManager{m.xdg, m.systemd}
+}
+
+func (m *linuxStartupManager) Enable(entry StartupEntry) error {
+	managers := m.managers()
+	if err := managers[0].Enable(entry); err != nil {
+		return err
+	}
+	if err := managers[1].Disable(); err != nil {
+		return fmt.Errorf("failed to remove previous autostart entry: %w", err)
+	}
+	return nil
+}
+
+func (m *linuxStartupManager) Disable() error {
+	for _, manager := range m.managers() {
+		if err := manager.Disable(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *linuxStartupManager) Status() (StartupStatus, error) {
+	for _, manager := range m.managers() {
+		status, err := manager.Status()
+		if err != nil {
+			return StartupStatus{}, err
+		}
+		if status.Enabled {
+			return status, nil
+		}
+	}
+	return StartupStatus{}, nil
+}
+
+// xdgAutostartManager registers the app with an XDG autostart desktop
+// entry in ~/.config/autostart
+type xdgAutostartManager struct {
+	homeDir string
+}
+
+// newXDGAutostartManager returns a startup manager for the user whose home
+// directory is homeDir
+func newXDGAutostartManager(homeDir string) *xdgAutostartManager {
+	return &xdgAutostartManager{homeDir: homeDir}
+}
+
+// desktopPath returns the path of the autostart desktop entry
+func (m *xdgAutostartManager) desktopPath() string {
+	return filepath.Join(m.homeDir, ".config", "autostart", startupAppName+".desktop")
+}
+
+func (m *xdgAutostartManager) Enable(entry StartupEntry) error {
+	if err := os.MkdirAll(filepath.Dir(m.desktopPath()), 0755); err != nil {
+		return err
+	}
+
+	desktopContent := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=%s
+Exec=%s
+Terminal=false
+X-GNOME-Autostart-enabled=true
+`, startupAppName, desktopExec(append([]string{entry.ExePath}, entry.Args...)))
+
+	return os.WriteFile(m.desktopPath(), []byte(desktopContent), 0644)
+}
+
+func (m *xdgAutostartManager) Disable() error {
+	if err := os.Remove(m.desktopPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (m *xdgAutostartManager) Status() (StartupStatus, error) {
+	content, err := os.ReadFile(m.desktopPath())
+	if os.IsNotExist(err) {
+		return StartupStatus{}, nil
+	}
+	if err != nil {
+		return StartupStatus{}, err
+	}
+
+	status := StartupStatus{Enabled: true, Mechanism: StartupMechanismXDG}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if exec, ok := strings.CutPrefix(scanner.Text(), "Exec="); ok {
+			status.ExePath, status.Args = parseStartupCommand(exec, parseDesktopExec)
+			break
+		}
+	}
+	return status, nil
+}
+
+// desktopExecReserved are the characters that require an Exec argument to
+// be quoted, per the Desktop Entry Specification
+const desktopExecReserved = " \t\n\"'\\><~|&;$*?#()`"
+
+// desktopExec formats args as the value of a desktop entry Exec key
+func desktopExec(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		arg = strings.ReplaceAll(arg, "%", "%%")
+		if arg == "" || strings.ContainsAny(arg, desktopExecReserved) {
+			arg = `"` + strings.NewReplacer(`"`, `\"`, "`", "\\`", "$", `\$`, `\`, `\\`).Replace(arg) + `"`
+		}
+		quoted[i] = arg
+	}
+	// String values escape backslashes once more before quoting applies
+	return strings.ReplaceAll(strings.Join(quoted, " "), `\`, `\\`)
+}
+
+// parseDesktopExec splits the value of a desktop entry Exec key into its
+// arguments, dropping field codes such as %U
+func parseDesktopExec(exec string) ([]string, error) {
+	exec = strings.NewReplacer(`\\`, `\`, `\s`, " ", `\t`, "\t", `\n`, "\n", `\r`, "\r").Replace(exec)
+
+	var args []string
+	var arg strings.Builder
+	inArg, quoted := false, false
+	for i := 0; i < len(exec); i++ {
+		c := exec[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(exec):
+			i++
+			arg.WriteByte(exec[i])
+		case c == '"':
+			quoted = !quoted
+			inArg = true
+		case !quoted && (c == ' ' || c == '\t'):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case c == '%' && i+1 < len(exec):
+			i++
+			if exec[i] == '%' {
+				arg.WriteByte('%')
+				inArg = true
+			}
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in Exec=%s", exec)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// startupTestArgs need quoting or escaping in desktop entries and units
+var startupTestArgs = []string{
+	"--profile", "work and play",
+	`--weather-location=Saint-Jean-d'Angély "centre"`,
+	"--tray-label", "{temp}% at 100%",
+	"$HOME", "${USER}", `C:\Temp`, "`date`", "%U", "",
+}
+
+// fakeSystemctl links and unlinks the unit like "systemctl --user" and
+// records the commands it was given
+func fakeSystemctl(m *systemdStartupManager, calls *[]string) func(args ...string) error {
+	return func(args ...string) error {
+		*calls = append(*calls, args[0])
+		switch args[0] {
+		case "enable":
+			if err := os.MkdirAll(filepath.Dir(m.wantsPath()), 0755); err != nil {
+				return err
+			}
+			return os.Symlink(m.unitPath(), m.wantsPath())
+		case "disable":
+			if err := os.Remove(m.wantsPath()); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// newTestLinuxStartupManager returns the manager for mechanism with a fake
+// systemctl
+func newTestLinuxStartupManager(t *testing.T, mechanism string, calls *[]string) *linuxStartupManager {
+	t.Helper()
+	manager, err := newStartupManager(&StartupConfig{Mechanism: mechanism})
+	if err != nil {
+		t.Fatal(err)
+	}
+	linux := manager.(*linuxStartupManager)
+	linux.systemd.systemctl = fakeSystemctl(linux.systemd, calls)
+	return linux
+}
+
+func TestLinuxStartupManagers(t *testing.T) {
+	tests := []struct {
+		mechanism string
+		file      func(m *linuxStartupManager) string
+		systemctl []string
+	}{
+		{StartupMechanismXDG, func(m *linuxStartupManager) string { return m.xdg.desktopPath() }, nil},
+		{StartupMechanismSystemd, func(m *linuxStartupManager) string { return m.systemd.unitPath() }, []string{"daemon-reload", "enable", "disable", "daemon-reload"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mechanism, func(t *testing.T) {
+			home := setTestHome(t)
+			var calls []string
+			manager := newTestLinuxStartupManager(t, tt.mechanism, &calls)
+
+			if status, err := manager.Status(); err != nil || status.Enabled {
+				t.Fatalf("Status before Enable = %+v, %v", status, err)
+			}
+
+			exePath := filepath.Join(home, "Applications", "my Weather App", "myWeatherApp")
+			if err := manager.Enable(StartupEntry{ExePath: exePath, Args: startupTestArgs}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(tt.file(manager)); err != nil {
+				t.Fatalf("startup entry not written: %v", err)
+			}
+
+			status, err := manager.Status()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := StartupStatus{Enabled: true, Mechanism: tt.mechanism, ExePath: exePath, Args: startupTestArgs}
+			if !reflect.DeepEqual(status, want) {
+				t.Fatalf("Status = %+v, want %+v", status, want)
+			}
+
+			// The executable does not exist, so the entry is stale
+			checked, err := startupStatus(manager)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !checked.Missing || checked.Current {
+				t.Errorf("startupStatus = %+v, want a missing executable", checked)
+			}
+
+			if err := manager.Disable(); err != nil {
+				t.Fatal(err)
+			}
+			if status, err := manager.Status(); err != nil || status.Enabled {
+				t.Fatalf("Status after Disable = %+v, %v", status, err)
+			}
+			if _, err := os.Stat(tt.file(manager)); !os.IsNotExist(err) {
+				t.Errorf("startup entry not removed: %v", err)
+			}
+			if !reflect.DeepEqual(calls, tt.systemctl) {
+				t.Errorf("systemctl calls = %v, want %v", calls, tt.systemctl)
+			}
+		})
+	}
+}
+
+func TestLinuxStartupSwitchMechanism(t *testing.T) {
+	setTestHome(t)
+	var calls []string
+	entry := StartupEntry{ExePath: "/opt/myWeatherApp/myWeatherApp", Args: []string{"--minimized"}}
+
+	xdg := newTestLinuxStartupManager(t, StartupMechanismXDG, &calls)
+	if err := xdg.Enable(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	systemd := newTestLinuxStartupManager(t, StartupMechanismSystemd, &calls)
+	if err := systemd.Enable(entry); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(xdg.xdg.desktopPath()); !os.IsNotExist(err) {
+		t.Error("desktop entry kept after switching to systemd")
+	}
+
+	// Either manager reports the entry that is registered
+	status, err := xdg.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Mechanism != StartupMechanismSystemd {
+		t.Errorf("Status mechanism = %q, want systemd", status.Mechanism)
+	}
+
+	if err := xdg.Enable(entry); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(systemd.systemd.unitPath()); !os.IsNotExist(err) {
+		t.Error("systemd unit kept after switching to xdg")
+	}
+}
+
+func TestSystemdUnlinkedUnitIsDisabled(t *testing.T) {
+	home := setTestHome(t)
+	manager := newSystemdStartupManager(home)
+	manager.systemctl = func(args ...string) error { return nil }
+
+	if err := manager.Enable(StartupEntry{ExePath: "/usr/bin/myWeatherApp"}); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := manager.Status(); err != nil || status.Enabled {
+		t.Errorf("Status of a unit that is not enabled = %+v, %v", status, err)
+	}
+}
+
+func TestDesktopExecRoundTrip(t *testing.T) {
+	tests := [][]string{
+		{"/usr/bin/myWeatherApp"},
+		{"/home/user/my apps/myWeatherApp", "--minimized"},
+		append([]string{"/opt/weather/myWeatherApp"}, startupTestArgs...),
+		{`/opt/a"b/myWeatherApp`, "it's", `back\slash`, "tab\there", "<>|&;*?#()~"},
+	}
+
+	for _, args := range tests {
+		exec := desktopExec(args)
+		got, err := parseDesktopExec(exec)
+		if err != nil {
+			t.Errorf("parseDesktopExec(%q): %v", exec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, args) {
+			t.Errorf("round trip of %q through Exec=%s = %q", args, exec, got)
+		}
+	}
+}
+
+func TestParseDesktopExecFieldCodes(t *testing.T) {
+	got, err := parseDesktopExec(`myWeatherApp %U --rate 100%% "a b"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"myWeatherApp", "--rate", "100%", "a b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDesktopExec = %q, want %q", got, want)
+	}
+
+	if _, err := parseDesktopExec(`myWeatherApp "unterminated`); err == nil {
+		t.Error("unterminated quote accepted")
+	}
+}
+
+func TestSystemdExecRoundTrip(t *testing.T) {
+	tests := [][]string{
+		{"/usr/bin/myWeatherApp"},
+		{"/home/user/my apps/myWeatherApp", "--minimized"},
+		append([]string{"/opt/weather/myWeatherApp"}, startupTestArgs...),
+		{"/opt/myWeatherApp", "%h", "%%", "$$", "line\nbreak", "tab\there", `"quoted"`},
+	}
+
+	for _, args := range tests {
+		execStart := systemdExec(args)
+		got, err := parseSystemdExec(execStart)
+		if err != nil {
+			t.Errorf("parseSystemdExec(%q): %v", execStart, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, args) {
+			t.Errorf("round trip of %q through ExecStart=%s = %q", args, execStart, got)
+		}
+	}
+}
+
+func TestXDGLegacyUnquotedExec(t *testing.T) {
+	home := setTestHome(t)
+	exe := filepath.Join(home, "John Smith", "myWeatherApp")
+	if err := os.MkdirAll(filepath.Dir(exe), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(exe, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := newXDGAutostartManager(home)
+	if err := os.MkdirAll(filepath.Dir(manager.desktopPath()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	entry := "[Desktop Entry]\nType=Application\nExec=" + exe + "\n"
+	if err := os.WriteFile(manager.desktopPath(), []byte(entry), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := manager.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ExePath != exe || len(status.Args) != 0 {
+		t.Errorf("Status of an unquoted Exec = %q, %q, want %q with no arguments", status.ExePath, status.Args, exe)
+	}
+}
+
+func TestRepairLegacyUnquotedExec(t *testing.T) {
+	home := setTestHome(t)
+	manager := newXDGAutostartManager(home)
+	if err := os.MkdirAll(filepath.Dir(manager.desktopPath()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The app has moved since the entry was written with an unquoted path
+	entry := "[Desktop Entry]\nType=Application\nExec=" + filepath.Join(home, "John Smith", "myWeatherApp") + "\n"
+	if err := os.WriteFile(manager.desktopPath(), []byte(entry), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	verification := (&App{}).checkStartup(true)
+	if verification.Error != "" {
+		t.Fatal(verification.Error)
+	}
+	status, err := manager.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Args) != 0 {
+		t.Errorf("repaired entry has arguments %q, want none", status.Args)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !darwin && !windows && !linux
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// newStartupManager reports that launching at login is not supported
+func newStartupManager(config *StartupConfig) (StartupManager, error) {
+	return nil, fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// systemdUnitName is the user unit that starts the app
+const systemdUnitName = startupAppName + ".service"
+
+// systemdTarget is the target the unit is installed into, started by
+// desktop sessions that integrate with systemd
+const systemdTarget = "graphical-session.target"
+
+// systemdStartupManager registers the app as a systemd user service in
+// ~/.config/systemd/user, restarted if it exits with an error
+type systemdStartupManager struct {
+	homeDir string
+	// systemctl runs "systemctl --user" with args
+	systemctl func(args ...string) error
+}
+
+// newSystemdStartupManager returns a startup manager for the user whose home
+// directory is homeDir
+func newSystemdStartupManager(homeDir string) *systemdStartupManager {
+	return &systemdStartupManager{homeDir: homeDir, systemctl: runSystemctl}
+}
+
+// runSystemctl runs systemctl for the user service manager
+func runSystemctl(args ...string) error {
+	output, err := exec.Command("systemctl", append([]string{"--user"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl --user %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// unitDir returns the directory of user units
+func (m *systemdStartupManager) unitDir() string {
+	return filepath.Join(m.homeDir, ".config", "systemd", "user")
+}
+
+// unitPath returns the path of the app's unit file
+func (m *systemdStartupManager) unitPath() string {
+	return filepath.Join(m.unitDir(), systemdUnitName)
+}
+
+// wantsPath returns the link "systemctl enable" creates for the unit
+func (m *systemdStartupManager) wantsPath() string {
+	return filepath.Join(m.unitDir(), systemdTarget+".wants", systemdUnitName)
+}
+
+func (m *systemdStartupManager) Enable(entry StartupEntry) error {
+	if err := os.MkdirAll(m.unitDir(), 0755); err != nil {
+		return err
+	}
+
+	unitContent := fmt.Sprintf(`[Unit]
+Description=%s weather tray app
+After=%s
+PartOf=%s
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=%s
+`, startupAppName, systemdTarget, systemdTarget, systemdExec(append([]string{entry.ExePath}, entry.Args...)), systemdTarget)
+
+	if err := os.WriteFile(m.unitPath(), []byte(unitContent), 0644); err != nil {
+		return err
+	}
+	if err := m.systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	return m.systemctl("enable", systemdUnitName)
+}
+
+func (m *systemdStartupManager) Disable() error {
+	if _, err := os.Stat(m.unitPath()); os.IsNotExist(err) {
+		return nil
+	}
+
+	if err := m.systemctl("disable", systemdUnitName); err != nil {
+		return err
+	}
+	if err := os.Remove(m.unitPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return m.systemctl("daemon-reload")
+}
+
+func (m *systemdStartupManager) Status() (StartupStatus, error) {
+	// A unit file that is not linked into the target does not start
+	if _, err := os.Lstat(m.wantsPath()); os.IsNotExist(err) {
+		return StartupStatus{}, nil
+	}
+	content, err := os.ReadFile(m.unitPath())
+	if os.IsNotExist(err) {
+		return StartupStatus{}, nil
+	}
+	if err != nil {
+		return StartupStatus{}, err
+	}
+
+	status := StartupStatus{Enabled: true, Mechanism: StartupMechanismSystemd}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if execStart, ok := strings.CutPrefix(scanner.Text(), "ExecStart="); ok {
+			status.ExePath, status.Args = parseStartupCommand(execStart, parseSystemdExec)
+			break
+		}
+	}
+	return status, nil
+}
+
+// systemdExec formats args as an ExecStart command line. Every argument is
+// quoted, and "%" and "$" are doubled so systemd does not expand them.
+func systemdExec(args []string) string {
+	quoted := make([]string, len(args))
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$", "\n", `\n`, "\t", `\t`)
+	for i, arg := range args {
+		quoted[i] = `"` + escaper.Replace(arg) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// parseSystemdExec splits an ExecStart command line written by systemdExec
+func parseSystemdExec(execStart string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg, quoted := false, false
+	for i := 0; i < len(execStart); i++ {
+		c := execStart[i]
+		switch {
+		case c == '\\' && i+1 < len(execStart):
+			i++
+			switch execStart[i] {
+			case 'n':
+				arg.WriteByte('\n')
+			case 't':
+				arg.WriteByte('\t')
+			default:
+				arg.WriteByte(execStart[i])
+			}
+			inArg = true
+		case (c == '%' || c == '$') && i+1 < len(execStart) && execStart[i+1] == c:
+			i++
+			arg.WriteByte(c)
+			inArg = true
+		case c == '"':
+			quoted = !quoted
+			inArg = true
+		case !quoted && (c == ' ' || c == '\t'):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in ExecStart=%s", execStart)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
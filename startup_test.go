@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStartupCommand(t *testing.T) {
+	// Legacy entries held the path unquoted, like C:\Users\John Smith\...
+	dir := filepath.Join(t.TempDir(), "John Smith", "my app")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	exe := filepath.Join(dir, "myWeatherApp")
+	if err := os.WriteFile(exe, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	moved := filepath.Join(filepath.Dir(dir), "old app", "myWeatherApp")
+
+	quoted := func(command string) ([]string, error) {
+		path, rest, _ := strings.Cut(strings.TrimPrefix(command, `"`), `" `)
+		return append([]string{path}, strings.Fields(rest)...), nil
+	}
+	fields := func(command string) ([]string, error) { return strings.Fields(command), nil }
+	failing := func(string) ([]string, error) { return nil, errors.New("unterminated quote") }
+
+	tests := []struct {
+		name    string
+		command string
+		split   func(string) ([]string, error)
+		exe     string
+		args    []string
+	}{
+		{"quoted", `"` + exe + `" --minimized --profile work`, quoted, exe, []string{"--minimized", "--profile", "work"}},
+		{"unquoted legacy", exe, fields, exe, nil},
+		{"unquoted legacy in quotes", `"` + exe + `"`, fields, exe, nil},
+		{"moved unquoted legacy", moved, fields, strings.Fields(moved)[0], strings.Fields(moved)[1:]},
+		{"moved with options", `"` + moved + `" --minimized`, quoted, moved, []string{"--minimized"}},
+		{"unparsable", `"` + exe, failing, exe, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exe, args := parseStartupCommand(tt.command, tt.split)
+			if exe != tt.exe || !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("parseStartupCommand(%q) = %q, %q, want %q, %q", tt.command, exe, args, tt.exe, tt.args)
+			}
+		})
+	}
+}
+
+func TestValidStartupArgs(t *testing.T) {
+	tests := []struct {
+		args  []string
+		valid bool
+	}{
+		{nil, true},
+		{[]string{"--minimized", "--profile", "work"}, true},
+		{[]string{"--launch-delay=30"}, true},
+		// Fragments of an unquoted path such as C:\Users\John Smith\...
+		{[]string{`Smith\AppData\myWeatherApp.exe`}, false},
+		{[]string{"--no-such-flag"}, false},
+	}
+
+	for _, tt := range tests {
+		if valid := validStartupArgs(tt.args); valid != tt.valid {
+			t.Errorf("validStartupArgs(%q) = %v, want %v", tt.args, valid, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// runKeyPath is the registry key of programs run at login
+const runKeyPath = `Software\Microsoft\Windows\CurrentVersion\Run`
+
+// registryStartupManager registers the app under the current user's Run key
+type registryStartupManager struct{}
+
+// newStartupManager returns the Windows startup manager
+func newStartupManager(config *StartupConfig) (StartupManager, error) {
+	return &registryStartupManager{}, nil
+}
+
+func (m *registryStartupManager) Enable(entry StartupEntry) error {
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	// The executable is always quoted so paths with spaces are not split
+	args := []string{`"` + entry.ExePath + `"`}
+	for _, arg := range entry.Args {
+		args = append(args, windows.EscapeArg(arg))
+	}
+
+	return key.SetStringValue(startupAppName, strings.Join(args, " "))
+}
+
+func (m *registryStartupManager) Disable() error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+	if errors.Is(err, registry.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	if err := key.DeleteValue(startupAppName); err != nil && !errors.Is(err, registry.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (m *registryStartupManager) Status() (StartupStatus, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE)
+	if errors.Is(err, registry.ErrNotExist) {
+		return StartupStatus{}, nil
+	}
+	if err != nil {
+		return StartupStatus{}, err
+	}
+	defer key.Close()
+
+	command, _, err := key.GetStringValue(startupAppName)
+	if errors.Is(err, registry.ErrNotExist) {
+		return StartupStatus{}, nil
+	}
+	if err != nil {
+		return StartupStatus{}, err
+	}
+
+	status := StartupStatus{Enabled: true, Mechanism: StartupMechanismRegistry}
+	status.ExePath, status.Args = parseStartupCommand(command, windows.DecomposeCommandLine)
+	return status, nil
+}
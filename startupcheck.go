@@ -0,0 +1,153 @@
+package main
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// StartupVerification is the result of checking the startup entry against
+// the running executable
+type StartupVerification struct {
+	CheckedAt time.Time      `json:"checkedAt"`
+	Status    *StartupStatus `json:"status,omitempty"`
+	// Stale reports an entry that does not start the running executable
+	Stale bool `json:"stale"`
+	// Repaired reports that the entry was re-registered for the running
+	// executable
+	Repaired bool   `json:"repaired"`
+	Error    string `json:"error,omitempty"`
+}
+
+// startupState holds the latest startup verification
+type startupState struct {
+	mu           sync.Mutex
+	verification *StartupVerification
+}
+
+// GetStartupVerification returns the result of the startup check made at
+// launch or by RepairStartup, or nil before the first check completes
+func (a *App) GetStartupVerification() *StartupVerification {
+	a.startup.mu.Lock()
+	defer a.startup.mu.Unlock()
+	return a.startup.verification
+}
+
+// RepairStartup re-registers a stale startup entry for the running
+// executable, keeping its arguments
+func (a *App) RepairStartup() *StartupVerification {
+	return a.verifyStartup(true)
+}
+
+// verifyStartup checks that an enabled startup entry starts the running
+// executable. An entry whose executable no longer exists, e.g. after the
+// app was moved, is repaired when startup.repairStale is set. An entry for
+// another copy that still exists is only reported, unless force is set.
+func (a *App) verifyStartup(force bool) *StartupVerification {
+	verification := a.checkStartup(force)
+	if verification.Error != "" {
+		log.Printf("Startup check failed: %s", verification.Error)
+	} else if verification.Repaired {
+		log.Printf("Startup entry repaired to start %s", verification.Status.ExePath)
+	} else if verification.Stale {
+		log.Printf("Startup entry starts %s instead of this executable", verification.Status.ExePath)
+	}
+
+	a.startup.mu.Lock()
+	a.startup.verification = verification
+	a.startup.mu.Unlock()
+
+	return verification
+}
+
+// checkStartup compares the startup entry with the running executable and
+// repairs it if needed
+func (a *App) checkStartup(force bool) *StartupVerification {
+	verification := &StartupVerification{CheckedAt: time.Now()}
+
+	config, err := a.LoadConfig()
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+
+	manager, err := newStartupManager(&config.Startup)
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+
+	status, err := startupStatus(manager)
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+	verification.Status = status
+
+	if !status.Enabled || status.Current {
+		return verification
+	}
+	verification.Stale = true
+
+	if !force && !(status.Missing && config.Startup.RepairStale) {
+		return verification
+	}
+
+	// Repair the entry with the mechanism it was registered with, even if
+	// the configured one has changed since
+	config.Startup.Mechanism = status.Mechanism
+	manager, err = newStartupManager(&config.Startup)
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+
+	exePath, err := installedExecutable()
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+	// Arguments that would not start the app are dropped rather than
+	// carried into the repaired entry
+	args := status.Args
+	if !validStartupArgs(args) {
+		log.Printf("Dropping invalid arguments %q from the startup entry", args)
+		args = nil
+	}
+	if err := manager.Enable(StartupEntry{ExePath: exePath, Args: args}); err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+
+	repaired, err := startupStatus(manager)
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+	verification.Status = repaired
+	verification.Stale = !repaired.Current
+	verification.Repaired = repaired.Current
+
+	return verification
+}
+
+// startupStatus reads the startup entry and compares it with the running
+// executable
+func startupStatus(manager StartupManager) (*StartupStatus, error) {
+	status, err := manager.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	if status.Enabled {
+		if _, err := os.Stat(status.ExePath); os.IsNotExist(err) {
+			status.Missing = true
+		}
+		if exePath, err := installedExecutable(); err == nil {
+			status.Current = sameFile(status.ExePath, exePath)
+		}
+	}
+
+	return &status, nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// tooltipEllipsis marks a truncated tooltip line
+const tooltipEllipsis = "…"
+
+// trayTooltipTemplate returns the configured tooltip template, or the
+// default one in the configured language
+func trayTooltipTemplate(config *AppConfig) string {
+	if config.TrayTooltip == "" {
+		return translate(config.Language, "ui.tooltipTemplate")
+	}
+	return config.TrayTooltip
+}
+
+// formatTrayTooltip fills the tooltip template and fits it to the platform
+// length limit
+func formatTrayTooltip(template string, weather *WeatherData, config *AppConfig) string {
+	return truncateTooltip(formatTrayLabel(template, weather, config), platformTooltipLimit())
+}
+
+// truncateTooltip shortens text to limit UTF-16 code units, the unit
+// Windows measures tooltips in. Whole lines are dropped from the end first;
+// a first line that is too long on its own is cut and ends in an ellipsis.
+// A limit of 0 means no limit.
+func truncateTooltip(text string, limit int) string {
+	if limit <= 0 || utf16Length(text) <= limit {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	kept := lines[:0]
+	length := 0
+	for i, line := range lines {
+		lineLength := utf16Length(line)
+		if i > 0 {
+			lineLength++ // newline
+		}
+		if length+lineLength > limit {
+			break
+		}
+		kept = append(kept, line)
+		length += lineLength
+	}
+	if len(kept) > 0 {
+		return strings.Join(kept, "\n")
+	}
+
+	// The first line alone is too long
+	budget := limit - utf16Length(tooltipEllipsis)
+	var b strings.Builder
+	length = 0
+	for _, r := range lines[0] {
+		size := utf16.RuneLen(r)
+		if size < 0 {
+			size = 1
+		}
+		if length+size > budget {
+			break
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	return strings.TrimRight(b.String(), " ") + tooltipEllipsis
+}
+
+// utf16Length returns the number of UTF-16 code units in s
+func utf16Length(s string) int {
+	length := 0
+	for _, r := range s {
+		if size := utf16.RuneLen(r); size > 0 {
+			length += size
+		} else {
+			length++
+		}
+	}
+	return length
+}
+
+// PreviewTrayTooltip formats a tooltip template with the latest weather,
+// truncated as it would be on this platform
+func (w *WeatherService) PreviewTrayTooltip(template string) (string, error) {
+	if err := validateLabelTemplate("trayTooltip", template); err != nil {
+		return "", err
+	}
+
+	config, err := w.app.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	weather, err := w.latestWeather()
+	if err != nil {
+		return "", err
+	}
+
+	return formatTrayTooltip(template, weather, config), nil
+}
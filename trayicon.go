@@ -2,70 +2,169 @@ package main
 
 import (
 	"bytes"
+	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
 	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 
-	"github.com/golang/freetype/truetype"
+	xdraw "golang.org/x/image/draw"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
-	"golang.org/x/image/font/gofont/gobold"
 	"golang.org/x/image/math/fixed"
 )
 
-// generateTrayIcon creates a tray icon with temperature text
-func generateTrayIcon(temperature float64) ([]byte, error) {
-	// Create a 64x64 image for the tray icon
-	size := 64
-	img := image.NewRGBA(image.Rect(0, 0, size, size))
+// Tray icon styles selectable with TrayIconConfig.Style
+const (
+	TrayIconStyleNumber       = "number"
+	TrayIconStyleGlyph        = "glyph"
+	TrayIconStyleGlyphNumber  = "glyph-number"
+	TrayIconStyleTemplate     = "template"
+	TrayIconStyleHighContrast = "high-contrast"
+	TrayIconStyleTemperature  = "temperature"
+)
 
-	// Fill with a gradient background (blue to purple)
-	for y := 0; y < size; y++ {
-		for x := 0; x < size; x++ {
-			// Calculate gradient
-			ratio := float64(y) / float64(size)
-			r := uint8(102 + (118-102)*ratio)
-			g := uint8(126 + (75-126)*ratio)
-			b := uint8(234 + (162-234)*ratio)
-			img.Set(x, y, color.RGBA{r, g, b, 255})
-		}
-	}
+// TrayIconConfig selects how the tray icon is drawn
+type TrayIconConfig struct {
+	Style string `json:"style"`
+	// ShowDegree appends a degree sign to the temperature
+	ShowDegree bool `json:"showDegree,omitempty"`
+	// TrueMinus draws negative values with U+2212 MINUS SIGN instead of a
+	// hyphen, which is wider and sits at the height of the digits
+	TrueMinus bool `json:"trueMinus,omitempty"`
+	// FontFile is a TTF or OTF font for the temperature; the embedded Go
+	// bold font is used when empty or unreadable
+	FontFile string `json:"fontFile,omitempty"`
+	// TextColor is a "#rrggbb" colour; by default the text contrasts with
+	// the background
+	TextColor string `json:"textColor,omitempty"`
+	// Background is the shape behind the number: circle, rounded or none
+	Background string           `json:"background,omitempty"`
+	Palette    *TrayIconPalette `json:"palette,omitempty"`
+	// ColorScale configures the temperature style
+	ColorScale *ColorScale `json:"colorScale,omitempty"`
+	// AnimationFPS is the frame rate of the refresh and alert animations;
+	// 0 disables them
+	AnimationFPS int `json:"animationFps"`
+}
 
-	// Draw circular shape to make it look better
-	center := size / 2
-	radius := size / 2
-	for y := 0; y < size; y++ {
-		for x := 0; x < size; x++ {
-			dx := x - center
-			dy := y - center
-			if dx*dx+dy*dy > radius*radius {
-				img.Set(x, y, color.RGBA{0, 0, 0, 0}) // Transparent outside circle
-			}
-		}
+// TrayIconOptions describes one icon to render
+type TrayIconOptions struct {
+	// Size is the edge length in pixels
+	Size int
+	// Text is the formatted temperature in the configured unit
+	Text string
+	// Config is the tray icon configuration the icon is drawn with
+	Config *TrayIconConfig
+}
+
+// font returns the font the temperature is drawn with
+func (o *TrayIconOptions) font() (trayFont, error) {
+	return loadTrayFont(o.Config.FontFile)
+}
+
+// TrayIconRenderer draws the tray icon for the current weather
+type TrayIconRenderer interface {
+	Render(weather *WeatherData, options *TrayIconOptions) (*image.RGBA, error)
+}
+
+// TrayIconRendererFunc adapts a function to a TrayIconRenderer
+type TrayIconRendererFunc func(weather *WeatherData, options *TrayIconOptions) (*image.RGBA, error)
+
+// Render calls f(weather, options)
+func (f TrayIconRendererFunc) Render(weather *WeatherData, options *TrayIconOptions) (*image.RGBA, error) {
+	return f(weather, options)
+}
+
+// trayIconRenderers holds the renderer for each style
+var trayIconRenderers = make(map[string]TrayIconRenderer)
+
+func init() {
+	registerTrayIconRenderer(TrayIconStyleNumber, TrayIconRendererFunc(renderNumberIcon))
+	registerTrayIconRenderer(TrayIconStyleGlyph, TrayIconRendererFunc(renderGlyphIcon))
+	registerTrayIconRenderer(TrayIconStyleGlyphNumber, TrayIconRendererFunc(renderGlyphNumberIcon))
+	registerTrayIconRenderer(TrayIconStyleTemplate, TrayIconRendererFunc(renderTemplateIcon))
+	registerTrayIconRenderer(TrayIconStyleHighContrast, TrayIconRendererFunc(renderHighContrastIcon))
+	registerTrayIconRenderer(TrayIconStyleTemperature, TrayIconRendererFunc(renderTemperatureIcon))
+}
+
+// registerTrayIconRenderer adds or replaces the renderer for a style. New
+// styles register here and become selectable in trayIcon.style.
+func registerTrayIconRenderer(style string, renderer TrayIconRenderer) {
+	trayIconRenderers[style] = renderer
+}
+
+// trayIconStyles returns the names of all registered styles
+func trayIconStyles() []string {
+	styles := make([]string, 0, len(trayIconRenderers))
+	for style := range trayIconRenderers {
+		styles = append(styles, style)
 	}
+	sort.Strings(styles)
+	return styles
+}
+
+// GetTrayIconStyles returns the tray icon styles the user can choose from
+func (a *App) GetTrayIconStyles() []string {
+	return trayIconStyles()
+}
 
-	// Prepare temperature text
-	tempStr := strconv.Itoa(int(temperature)) + "°"
+// isTemplateTrayIcon reports whether a style draws a macOS template image,
+// which the menu bar recolours for light and dark mode
+func isTemplateTrayIcon(config *TrayIconConfig) bool {
+	return config.Style == TrayIconStyleTemplate
+}
+
+// trayIconSizes are the logical icon sizes generated for the platforms: 16
+// and 32 for the Windows notification area, 22 and 24 for macOS and Linux
+// panels, 48 and 64 for large panels and previews
+var trayIconSizes = []int{16, 22, 24, 32, 48, 64}
 
-	// Draw text in the center
-	point := fixed.Point26_6{
-		X: fixed.I(size/2 - len(tempStr)*3),
-		Y: fixed.I(size/2 + 7),
+// TrayIconImage is one rendered tray icon size
+type TrayIconImage struct {
+	Size  int    `json:"size"`
+	Scale int    `json:"scale"`
+	PNG   []byte `json:"png"`
+}
+
+// Name returns the conventional file name suffix, e.g. "22" or "22@2x"
+func (i TrayIconImage) Name() string {
+	if i.Scale == 1 {
+		return strconv.Itoa(i.Size)
 	}
+	return fmt.Sprintf("%d@%dx", i.Size, i.Scale)
+}
 
-	d := &font.Drawer{
-		Dst:  img,
-		Src:  image.NewUniform(color.RGBA{255, 255, 255, 255}),
-		Face: basicfont.Face7x13,
-		Dot:  point,
+// generateTrayIconSet renders every tray icon size at 1x and 2x
+func generateTrayIconSet(weather *WeatherData, config *AppConfig) ([]TrayIconImage, error) {
+	images := make([]TrayIconImage, 0, len(trayIconSizes)*2)
+	for _, size := range trayIconSizes {
+		for _, scale := range []int{1, 2} {
+			data, err := generateTrayIcon(weather, config, size*scale)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, TrayIconImage{Size: size, Scale: scale, PNG: data})
+		}
+	}
+	return images, nil
+}
+
+// generateTrayIcon renders the tray icon at the given pixel size as a PNG
+func generateTrayIcon(weather *WeatherData, config *AppConfig, pixels int) ([]byte, error) {
+	img, err := renderTrayIcon(weather, config, pixels)
+	if err != nil {
+		return nil, err
 	}
-	d.DrawString(tempStr)
 
-	// Convert to PNG bytes
+	// Convert to PNG
 	var buf bytes.Buffer
-	err := png.Encode(&buf, img)
+	err = png.Encode(&buf, img)
 	if err != nil {
 		return nil, err
 	}
@@ -73,107 +172,294 @@ func generateTrayIcon(temperature float64) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// generateTrayIconWithWeather creates a tray icon based on weather data
-func generateTrayIconWithWeather(weather *WeatherData) ([]byte, error) {
-	// Create a 64x64 image
-	size := 64
+// renderTrayIcon draws the tray icon at the given pixel size
+func renderTrayIcon(weather *WeatherData, config *AppConfig, pixels int) (*image.RGBA, error) {
+	style := config.TrayIcon.Style
+	if style == "" {
+		style = TrayIconStyleNumber
+	}
+
+	renderer, ok := trayIconRenderers[style]
+	if !ok {
+		return nil, fmt.Errorf("unknown tray icon style: %s", style)
+	}
+
+	unit := temperatureUnit(config)
+	return renderer.Render(weather, &TrayIconOptions{
+		Size:   pixels,
+		Text:   temperatureText(convertTemperature(weather.Temperature, unit), &config.TrayIcon),
+		Config: &config.TrayIcon,
+	})
+}
+
+// ExportTrayIcons writes the current tray icon at every size to dir, named
+// tray-<size>.png and tray-<size>@2x.png
+func (w *WeatherService) ExportTrayIcons(dir string) error {
+	weather, err := w.GetWeather("")
+	if err != nil {
+		return err
+	}
+
+	config, err := w.app.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	images, err := generateTrayIconSet(weather, config)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, img := range images {
+		path := filepath.Join(dir, "tray-"+img.Name()+".png")
+		if err := os.WriteFile(path, img.PNG, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// temperatureText rounds the temperature to the nearest integer, halves away
+// from zero, and formats it for the icon. Values that round to zero never
+// show a minus sign.
+func temperatureText(temperature float64, config *TrayIconConfig) string {
+	rounded := int(math.Round(temperature))
+
+	text := strconv.Itoa(rounded)
+	if rounded < 0 && config.TrueMinus {
+		text = "\u2212" + strconv.Itoa(-rounded)
+	}
+	if config.ShowDegree {
+		text += "°"
+	}
+	return text
+}
+
+// renderNumberIcon draws the temperature on a background coloured by the
+// palette. Without a background the number itself takes the colour.
+func renderNumberIcon(weather *WeatherData, options *TrayIconOptions) (*image.RGBA, error) {
+	return drawNumberIcon(options, backgroundColor(weather, options.Config)), nil
+}
+
+// renderTemperatureIcon draws the temperature on a background taken from the
+// continuous colour scale
+func renderTemperatureIcon(weather *WeatherData, options *TrayIconOptions) (*image.RGBA, error) {
+	return drawNumberIcon(options, temperatureScaleColor(weather, options.Config.ColorScale)), nil
+}
+
+// drawNumberIcon draws the temperature on the configured background shape
+func drawNumberIcon(options *TrayIconOptions, background color.RGBA) *image.RGBA {
+	size := options.Size
 	img := image.NewRGBA(image.Rect(0, 0, size, size))
 
-	// Determine background color based on weather condition
-	var bgColor color.RGBA
-	switch weather.Condition {
-	case "Sunny":
-		bgColor = color.RGBA{255, 193, 7, 255} // Amber/Yellow
-	case "Partly Cloudy":
-		bgColor = color.RGBA{158, 158, 158, 255} // Gray
-	case "Cloudy":
-		bgColor = color.RGBA{117, 117, 117, 255} // Dark gray
-	case "Rainy":
-		bgColor = color.RGBA{33, 150, 243, 255} // Blue
-	case "Stormy":
-		bgColor = color.RGBA{63, 81, 181, 255} // Dark blue
-	case "Snowy":
-		bgColor = color.RGBA{224, 247, 250, 255} // Light cyan
-	case "Foggy":
-		bgColor = color.RGBA{189, 189, 189, 255} // Light gray
+	switch options.Config.Background {
+	case TrayIconBackgroundNone:
+		drawOutlinedTemperature(img, img.Bounds(), options, background, color.RGBA{0, 0, 0, 255})
+	case TrayIconBackgroundRounded:
+		fillBackground(img, TrayIconBackgroundRounded, background)
+		drawTemperature(img, img.Bounds(), options, textColor(options.Config, background))
 	default:
-		bgColor = color.RGBA{102, 126, 234, 255} // Default purple
+		fillBackground(img, TrayIconBackgroundCircle, background)
+		drawTemperature(img, circleTextRect(size), options, textColor(options.Config, background))
 	}
+	return img
+}
 
-	// Fill background
-	draw.Draw(img, img.Bounds(), &image.Uniform{bgColor}, image.Point{}, draw.Src)
+// circleTextRect returns the text area of a circular icon, narrowed so wide
+// values stay inside the circle
+func circleTextRect(size int) image.Rectangle {
+	inset := size / 10
+	return image.Rect(inset, 0, size-inset, size)
+}
 
-	// Draw circular shape
-	center := size / 2
-	radius := size / 2
-	for y := 0; y < size; y++ {
-		for x := 0; x < size; x++ {
-			dx := x - center
-			dy := y - center
-			if dx*dx+dy*dy > radius*radius {
-				img.Set(x, y, color.RGBA{0, 0, 0, 0})
-			}
-		}
+// contrastingTextColor returns near-black text for light backgrounds and
+// white text for dark ones
+func contrastingTextColor(background color.RGBA) color.RGBA {
+	luminance := 0.299*float64(background.R) + 0.587*float64(background.G) + 0.114*float64(background.B)
+	if luminance < 110 {
+		return color.RGBA{255, 255, 255, 255}
 	}
+	return color.RGBA{1, 1, 1, 255}
+}
+
+// renderGlyphIcon draws only the condition glyph
+func renderGlyphIcon(weather *WeatherData, options *TrayIconOptions) (*image.RGBA, error) {
+	size := options.Size
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	drawConditionGlyph(img, img.Bounds(), weather, nil)
+	return img, nil
+}
+
+// renderGlyphNumberIcon draws the condition glyph with the temperature in
+// front of its lower half
+func renderGlyphNumberIcon(weather *WeatherData, options *TrayIconOptions) (*image.RGBA, error) {
+	size := options.Size
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	drawConditionGlyph(img, image.Rect(0, 0, size*3/4, size*3/4), weather, nil)
+	drawOutlinedTemperature(img, image.Rect(0, size/3, size, size), options,
+		textColor(options.Config, color.RGBA{0, 0, 0, 255}), color.RGBA{0, 0, 0, 255})
+	return img, nil
+}
 
-	// Temperature text - just the number, no degree symbol for better visibility
-	tempStr := strconv.Itoa(int(weather.Temperature + 0.5)) // Round to nearest integer
+// renderTemplateIcon draws a monochrome glyph and temperature. Only the alpha
+// channel matters for macOS template images.
+func renderTemplateIcon(weather *WeatherData, options *TrayIconOptions) (*image.RGBA, error) {
+	size := options.Size
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	black := color.RGBA{0, 0, 0, 255}
+	drawConditionGlyph(img, image.Rect(0, 0, size*3/4, size*3/4), weather, black)
 
-	// Use a larger font
-	ft, err := truetype.Parse(gobold.TTF)
+	// Clear the area behind the number so it stays readable
+	textArea := image.Rect(0, size/3, size, size)
+	mask := image.NewRGBA(img.Bounds())
+	drawTemperature(mask, textArea, options, black)
+	dilateAlpha(mask, size/16+1)
+	eraseMask(img, mask)
+
+	drawTemperature(img, textArea, options, black)
+	return img, nil
+}
+
+// renderHighContrastIcon draws white text on a black disc with a white ring
+func renderHighContrastIcon(weather *WeatherData, options *TrayIconOptions) (*image.RGBA, error) {
+	size := options.Size
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	center := float64(size) / 2
+	fillCircle(img, center, center, center, color.RGBA{255, 255, 255, 255})
+	fillCircle(img, center, center, center-float64(size)/16, color.RGBA{0, 0, 0, 255})
+	drawTemperature(img, circleTextRect(size), options, color.RGBA{255, 255, 255, 255})
+	return img, nil
+}
+
+// Text layout proportions relative to the target rectangle
+const (
+	textHeightRatio = 0.56 // digit height
+	textWidthRatio  = 0.92 // widest allowed text
+	textMinScale    = 0.75 // smallest font scale before glyphs are condensed
+)
+
+// digitHeight returns the ink height of a digit at 1pt, used to size text
+// from font metrics instead of fixed offsets
+func digitHeight(ft trayFont) (float64, error) {
+	face, err := ft.Face(100, font.HintingNone)
 	if err != nil {
-		// Fallback to basic font if truetype fails
-		return generateSimpleTrayIcon(weather, img)
+		return 0, err
 	}
-
-	// Create font face with much larger size (42pt)
-	face := truetype.NewFace(ft, &truetype.Options{
-		Size: 42,
-		DPI:  72,
-	})
 	defer face.Close()
 
-	// Measure text to center it
-	d := &font.Drawer{
-		Dst:  img,
-		Src:  image.NewUniform(color.RGBA{1, 1, 1, 255}),
-		Face: face,
+	bounds, _ := font.BoundString(face, "0")
+	return float64(bounds.Max.Y-bounds.Min.Y) / 64 / 100, nil
+}
+
+// textLayout sizes text for rect. Digits fill textHeightRatio of the height;
+// text wider than textWidthRatio of the width is first shrunk, down to
+// textMinScale, and then condensed horizontally by the returned factor.
+func textLayout(ft trayFont, rect image.Rectangle, text string) (font.Face, float64, error) {
+	height, err := digitHeight(ft)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// Get text bounds for centering
-	bounds, _ := d.BoundString(tempStr)
-	textWidth := (bounds.Max.X - bounds.Min.X).Ceil()
+	size := float64(rect.Dy()) * textHeightRatio / height
+	// Hinting snaps outlines to the pixel grid, which keeps small icons crisp
+	hinting := font.HintingNone
+	if rect.Dy() < 32 {
+		hinting = font.HintingFull
+	}
 
-	// Center the text (moved down 1 pixel)
-	point := fixed.Point26_6{
-		X: fixed.I((size - textWidth) / 2),
-		Y: fixed.I(size/2 + 11),
+	face, err := ft.Face(size, hinting)
+	if err != nil {
+		return nil, 0, err
+	}
+	bounds, _ := font.BoundString(face, text)
+	width := float64(bounds.Max.X-bounds.Min.X) / 64
+	maxWidth := float64(rect.Dx()) * textWidthRatio
+	if width <= maxWidth {
+		return face, 1, nil
 	}
 
-	d.Dot = point
-	d.DrawString(tempStr)
+	scale := math.Max(maxWidth/width, textMinScale)
+	face.Close()
+	face, err = ft.Face(size*scale, hinting)
+	if err != nil {
+		return nil, 0, err
+	}
+	return face, maxWidth / (width * scale), nil
+}
 
-	// Convert to PNG
-	var buf bytes.Buffer
-	err = png.Encode(&buf, img)
+// drawTemperature draws the temperature centred in rect. The horizontal
+// position uses the ink bounds of the text; the baseline uses the digit
+// height so values with and without a minus sign sit at the same height.
+func drawTemperature(img *image.RGBA, rect image.Rectangle, options *TrayIconOptions, textColor color.Color) {
+	text := options.Text
+	ft, err := options.font()
 	if err != nil {
-		return nil, err
+		// Fallback to basic font if the embedded font fails
+		drawSimpleText(img, rect, text, textColor)
+		return
 	}
 
-	return buf.Bytes(), nil
+	face, condense, err := textLayout(ft, rect, text)
+	if err != nil {
+		drawSimpleText(img, rect, text, textColor)
+		return
+	}
+	defer face.Close()
+
+	if condense >= 1 {
+		drawCenteredText(img, rect, text, face, textColor)
+		return
+	}
+
+	// Draw into a wider buffer and squeeze it into rect
+	wide := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(float64(rect.Dx())/condense)), rect.Dy()))
+	drawCenteredText(wide, wide.Bounds(), text, face, textColor)
+	xdraw.CatmullRom.Scale(img, rect, wide, wide.Bounds(), draw.Over, nil)
 }
 
-// generateSimpleTrayIcon is a fallback with large basic font
-func generateSimpleTrayIcon(weather *WeatherData, img *image.RGBA) ([]byte, error) {
-	size := 64
-	tempStr := strconv.Itoa(int(weather.Temperature))
+// drawCenteredText draws text with face centred in rect
+func drawCenteredText(img *image.RGBA, rect image.Rectangle, text string, face font.Face, textColor color.Color) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(textColor),
+		Face: face,
+	}
+
+	bounds, _ := d.BoundString(text)
+	digits, _ := d.BoundString("0")
 
+	centerX := fixed.I(rect.Min.X) + fixed.I(rect.Dx())/2
+	centerY := fixed.I(rect.Min.Y) + fixed.I(rect.Dy())/2
+	d.Dot = fixed.Point26_6{
+		X: centerX - (bounds.Min.X+bounds.Max.X)/2,
+		Y: centerY - (digits.Min.Y+digits.Max.Y)/2,
+	}
+	d.DrawString(text)
+}
+
+// drawOutlinedTemperature draws text with an outline so it stays legible on
+// top of a glyph
+func drawOutlinedTemperature(img *image.RGBA, rect image.Rectangle, options *TrayIconOptions, textColor, outline color.Color) {
+	mask := image.NewRGBA(img.Bounds())
+	drawTemperature(mask, rect, options, outline)
+	dilateAlpha(mask, rect.Dy()/24+1)
+	draw.Draw(img, img.Bounds(), mask, image.Point{}, draw.Over)
+
+	drawTemperature(img, rect, options, textColor)
+}
+
+// drawSimpleText is a fallback with large basic font
+func drawSimpleText(img *image.RGBA, rect image.Rectangle, text string, textColor color.Color) {
 	// Draw large text manually using bigger basic font
 	// Draw each character larger by drawing multiple times with offset
-	startX := (size - len(tempStr)*20) / 2
-	startY := size/2 + 5
+	startX := rect.Min.X + (rect.Dx()-len(text)*20)/2
+	startY := rect.Min.Y + rect.Dy()/2 + 5
 
-	for i, ch := range tempStr {
+	for i, ch := range text {
 		// Draw character multiple times to make it bold
 		for dx := 0; dx < 3; dx++ {
 			for dy := 0; dy < 3; dy++ {
@@ -183,7 +469,7 @@ func generateSimpleTrayIcon(weather *WeatherData, img *image.RGBA) ([]byte, erro
 				}
 				d := &font.Drawer{
 					Dst:  img,
-					Src:  image.NewUniform(color.RGBA{255, 255, 255, 255}),
+					Src:  image.NewUniform(textColor),
 					Face: basicfont.Face7x13,
 					Dot:  point,
 				}
@@ -191,8 +477,87 @@ func generateSimpleTrayIcon(weather *WeatherData, img *image.RGBA) ([]byte, erro
 			}
 		}
 	}
+}
 
-	var buf bytes.Buffer
-	err := png.Encode(&buf, img)
-	return buf.Bytes(), err
+// dilateAlpha grows every opaque pixel of img by radius pixels, used to
+// build text outlines
+func dilateAlpha(img *image.RGBA, radius int) {
+	src := image.NewRGBA(img.Bounds())
+	copy(src.Pix, img.Pix)
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var best color.RGBA
+			for dy := -radius; dy <= radius; dy++ {
+				for dx := -radius; dx <= radius; dx++ {
+					if dx*dx+dy*dy > radius*radius {
+						continue
+					}
+					p := image.Pt(x+dx, y+dy)
+					if !p.In(bounds) {
+						continue
+					}
+					if c := src.RGBAAt(p.X, p.Y); c.A > best.A {
+						best = c
+					}
+				}
+			}
+			img.SetRGBA(x, y, best)
+		}
+	}
+}
+
+// eraseMask clears img wherever mask is opaque, in proportion to its alpha
+func eraseMask(img, mask *image.RGBA) {
+	for i := 0; i+3 < len(img.Pix); i += 4 {
+		keep := 255 - uint32(mask.Pix[i+3])
+		for c := 0; c < 4; c++ {
+			img.Pix[i+c] = uint8(uint32(img.Pix[i+c]) * keep / 255)
+		}
+	}
+}
+
+// fillCircle fills a circle with an anti-aliased edge
+func fillCircle(img *image.RGBA, cx, cy, radius float64, c color.Color) {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			dx := float64(x) + 0.5 - cx
+			dy := float64(y) + 0.5 - cy
+			coverage := radius - math.Sqrt(dx*dx+dy*dy) + 0.5
+			if coverage <= 0 {
+				continue
+			}
+			blendPixel(img, x, y, c, coverage)
+		}
+	}
+}
+
+// blendPixel composites c over the pixel at x, y with the given coverage
+func blendPixel(img *image.RGBA, x, y int, c color.Color, coverage float64) {
+	if coverage > 1 {
+		coverage = 1
+	}
+	r, g, b, a := c.RGBA()
+	alpha := uint32(float64(a) * coverage)
+	if alpha == 0 {
+		return
+	}
+
+	src := color.RGBA64{
+		R: uint16(uint32(float64(r) * coverage)),
+		G: uint16(uint32(float64(g) * coverage)),
+		B: uint16(uint32(float64(b) * coverage)),
+		A: uint16(alpha),
+	}
+	dst := img.RGBAAt(x, y)
+	dr, dg, db, da := dst.RGBA()
+	inv := 0xffff - alpha
+	img.Set(x, y, color.RGBA64{
+		R: uint16(uint32(src.R) + dr*inv/0xffff),
+		G: uint16(uint32(src.G) + dg*inv/0xffff),
+		B: uint16(uint32(src.B) + db*inv/0xffff),
+		A: uint16(alpha + da*inv/0xffff),
+	})
 }
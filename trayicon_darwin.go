@@ -0,0 +1,12 @@
+package main
+
+// platformTrayIconSize returns the pixel size for the macOS menu bar: the
+// 22pt status item at @2x, which macOS scales down on non-Retina screens
+func platformTrayIconSize() int {
+	return 22 * 2
+}
+
+// platformTooltipLimit returns 0 as status item tooltips have no length limit
+func platformTooltipLimit() int {
+	return 0
+}
@@ -0,0 +1,15 @@
+//go:build !darwin && !windows
+
+package main
+
+// platformTrayIconSize returns the pixel size for StatusNotifierItem panels:
+// 24px at @2x, which panels scale down to their own height
+func platformTrayIconSize() int {
+	return 24 * 2
+}
+
+// platformTooltipLimit returns 0 as StatusNotifierItem tooltips have no
+// length limit
+func platformTooltipLimit() int {
+	return 0
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden tray icons in testdata")
+
+// trayIconCases are the weather conditions the golden icons are drawn for
+var trayIconCases = []struct {
+	name    string
+	weather WeatherData
+	unit    string
+}{
+	{name: "clear-day", weather: WeatherData{Temperature: 21.4, WeatherCode: 0, IsDay: true}, unit: TemperatureUnitCelsius},
+	{name: "rain-night", weather: WeatherData{Temperature: -3.7, WeatherCode: 61}, unit: TemperatureUnitCelsius},
+	{name: "thunderstorm-fahrenheit", weather: WeatherData{Temperature: 40.6, WeatherCode: 95, IsDay: true}, unit: TemperatureUnitFahrenheit},
+}
+
+func TestTrayIconRenderers(t *testing.T) {
+	want := []string{
+		TrayIconStyleGlyph, TrayIconStyleGlyphNumber, TrayIconStyleHighContrast,
+		TrayIconStyleNumber, TrayIconStyleTemperature, TrayIconStyleTemplate,
+	}
+	if styles := trayIconStyles(); !reflect.DeepEqual(styles, want) {
+		t.Errorf("trayIconStyles() = %v, want %v", styles, want)
+	}
+
+	for _, style := range want {
+		if err := validateTrayIconConfig(&TrayIconConfig{Style: style}); err != nil {
+			t.Errorf("validateTrayIconConfig(%s) = %v", style, err)
+		}
+	}
+	if err := validateTrayIconConfig(&TrayIconConfig{Style: "sparkles"}); err == nil {
+		t.Error("validateTrayIconConfig accepted an unregistered style")
+	}
+
+	registerTrayIconRenderer("sparkles", TrayIconRendererFunc(renderGlyphIcon))
+	defer delete(trayIconRenderers, "sparkles")
+	if err := validateTrayIconConfig(&TrayIconConfig{Style: "sparkles"}); err != nil {
+		t.Errorf("registered style rejected: %v", err)
+	}
+}
+
+func TestTrayIconSizes(t *testing.T) {
+	for _, style := range trayIconStyles() {
+		for _, pixels := range []int{16, 22, 32, 44, 64, 128} {
+			config := (&App{}).GetDefaultConfig()
+			config.TrayIcon.Style = style
+			weather := trayIconCases[1].weather
+
+			img, err := renderTrayIcon(&weather, config, pixels)
+			if err != nil {
+				t.Fatalf("%s at %dpx: %v", style, pixels, err)
+			}
+			if img.Bounds() != image.Rect(0, 0, pixels, pixels) {
+				t.Errorf("%s at %dpx: bounds %v", style, pixels, img.Bounds())
+			}
+			if opaquePixels(img) == 0 {
+				t.Errorf("%s at %dpx: empty icon", style, pixels)
+			}
+		}
+	}
+}
+
+func TestTrayIconGolden(t *testing.T) {
+	const pixels = 32
+
+	for _, style := range trayIconStyles() {
+		for _, tc := range trayIconCases {
+			name := fmt.Sprintf("%s-%s", style, tc.name)
+			t.Run(name, func(t *testing.T) {
+				config := (&App{}).GetDefaultConfig()
+				config.TrayIcon.Style = style
+				config.CustomSettings["temperatureUnit"] = tc.unit
+				weather := tc.weather
+
+				img, err := renderTrayIcon(&weather, config, pixels)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				path := filepath.Join("testdata", "trayicon", name+".png")
+				if *updateGolden {
+					writeGoldenPNG(t, path, img)
+					return
+				}
+				compareGoldenPNG(t, path, img)
+			})
+		}
+	}
+}
+
+func TestConditionGlyphBounds(t *testing.T) {
+	codes := []int{0, 1, 2, 3, 45, 48, 51, 56, 61, 66, 71, 77, 80, 85, 95, 96, 99, 1000}
+	rect := image.Rect(8, 8, 40, 40)
+
+	for _, code := range codes {
+		for _, isDay := range []bool{true, false} {
+			img := image.NewRGBA(image.Rect(0, 0, 48, 48))
+			drawConditionGlyph(img, rect, &WeatherData{WeatherCode: code, IsDay: isDay}, nil)
+
+			inside := 0
+			for y := 0; y < 48; y++ {
+				for x := 0; x < 48; x++ {
+					if img.RGBAAt(x, y).A == 0 {
+						continue
+					}
+					if !image.Pt(x, y).In(rect) {
+						t.Fatalf("code %d (day %v): pixel drawn outside the glyph rect at %d,%d", code, isDay, x, y)
+					}
+					inside++
+				}
+			}
+			if inside == 0 {
+				t.Errorf("code %d (day %v): nothing drawn", code, isDay)
+			}
+		}
+	}
+}
+
+// opaquePixels counts the pixels with any coverage
+func opaquePixels(img *image.RGBA) int {
+	n := 0
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func writeGoldenPNG(t *testing.T, path string, img *image.RGBA) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// compareGoldenPNG compares img with a golden file. Channels may differ by
+// a little, as fused multiply-add changes anti-aliasing on some platforms.
+func compareGoldenPNG(t *testing.T, path string, img *image.RGBA) {
+	t.Helper()
+	const tolerance = 2
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	defer file.Close()
+	golden, err := png.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if golden.Bounds() != img.Bounds() {
+		t.Fatalf("bounds %v, golden %v", img.Bounds(), golden.Bounds())
+	}
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			r1, g1, b1, a1 := img.At(x, y).RGBA()
+			r2, g2, b2, a2 := golden.At(x, y).RGBA()
+			for _, d := range []int{int(r1>>8) - int(r2>>8), int(g1>>8) - int(g2>>8), int(b1>>8) - int(b2>>8), int(a1>>8) - int(a2>>8)} {
+				if d > tolerance || d < -tolerance {
+					t.Fatalf("pixel %d,%d = %v, golden %v", x, y, img.At(x, y), golden.At(x, y))
+				}
+			}
+		}
+	}
+}
+
+func TestTemperatureText(t *testing.T) {
+	tests := []struct {
+		temperature float64
+		config      TrayIconConfig
+		want        string
+	}{
+		{21.4, TrayIconConfig{}, "21"},
+		{21.5, TrayIconConfig{}, "22"},
+		{-3.7, TrayIconConfig{}, "-4"},
+		{-2.5, TrayIconConfig{}, "-3"}, // halves round away from zero
+		{-0.4, TrayIconConfig{}, "0"},  // no "-0"
+		{-12, TrayIconConfig{TrueMinus: true}, "−12"},
+		{105.2, TrayIconConfig{ShowDegree: true}, "105°"},
+	}
+
+	for _, tt := range tests {
+		if got := temperatureText(tt.temperature, &tt.config); got != tt.want {
+			t.Errorf("temperatureText(%v, %+v) = %q, want %q", tt.temperature, tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestTextLayoutFitsWideValues(t *testing.T) {
+	ft, err := loadTrayFont("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pixels := range []int{16, 22, 32, 64} {
+		rect := circleTextRect(pixels)
+		for _, text := range []string{"7", "-12", "105", "−40°", "-100°"} {
+			face, condense, err := textLayout(ft, rect, text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bounds, _ := font.BoundString(face, text)
+			width := float64(bounds.Max.X-bounds.Min.X) / 64 * condense
+			face.Close()
+
+			if width > float64(rect.Dx())+0.5 {
+				t.Errorf("%q at %dpx is %.1fpx wide, rect is %dpx", text, pixels, width, rect.Dx())
+			}
+		}
+	}
+}
+
+func TestSaveConfigWithMovedFont(t *testing.T) {
+	home := setTestHome(t)
+	app := &App{}
+
+	fontPath := filepath.Join(home, "Font.ttf")
+	if err := os.WriteFile(fontPath, gobold.TTF, 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := app.GetDefaultConfig()
+	config.TrayIcon.FontFile = fontPath
+	if err := app.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unrelated changes still save after the font has been removed
+	if err := os.Remove(fontPath); err != nil {
+		t.Fatal(err)
+	}
+	config.WindowWidth = 500
+	if err := app.SaveConfig(config); err != nil {
+		t.Errorf("SaveConfig with a moved font = %v", err)
+	}
+	if err := app.SetSetting("weatherLocation", "Oslo"); err != nil {
+		t.Errorf("SetSetting with a moved font = %v", err)
+	}
+
+	// Choosing a font that cannot be read is still rejected
+	config.TrayIcon.FontFile = filepath.Join(home, "Other.ttf")
+	if err := app.SaveConfig(config); err == nil {
+		t.Error("SaveConfig accepted a missing font file")
+	}
+}
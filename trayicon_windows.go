@@ -0,0 +1,18 @@
+package main
+
+import "github.com/wailsapp/wails/v3/pkg/w32"
+
+// platformTrayIconSize returns the small icon size for the current DPI, e.g.
+// 16 at 100% scaling and 32 at 200%
+func platformTrayIconSize() int {
+	if size := int(w32.GetSystemMetrics(w32.SM_CXSMICON)); size > 0 {
+		return size
+	}
+	return 16
+}
+
+// platformTooltipLimit is the length of NOTIFYICONDATA.szTip in UTF-16 code
+// units, less the terminating NUL
+func platformTooltipLimit() int {
+	return 127
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+)
+
+// Background shapes selectable with TrayIconConfig.Background
+const (
+	TrayIconBackgroundCircle  = "circle"
+	TrayIconBackgroundRounded = "rounded"
+	TrayIconBackgroundNone    = "none"
+)
+
+// TrayIconPalette maps conditions or temperature bands to background colours.
+// Colours are "#rrggbb" or "#rrggbbaa".
+type TrayIconPalette struct {
+	// Conditions is keyed by condition category, e.g. "rain", with an
+	// optional ".night" suffix for the night variant, e.g. "clear.night"
+	Conditions map[string]string `json:"conditions,omitempty"`
+	// TemperatureBands take precedence over Conditions when set
+	TemperatureBands []TemperatureBand `json:"temperatureBands,omitempty"`
+}
+
+// TemperatureBand colours temperatures from Min °C up to the next band.
+// Bands are always in °C, also when temperatureUnit is fahrenheit, so a
+// palette keeps working when the unit is switched.
+type TemperatureBand struct {
+	Min   float64 `json:"min"`
+	Color string  `json:"color"`
+}
+
+// conditionCategories lists the categories a palette can be keyed by
+var conditionCategories = []string{
+	ConditionClear, ConditionPartlyCloudy, ConditionCloudy, ConditionFog,
+	ConditionDrizzle, ConditionRain, ConditionFreezingRain, ConditionSnow,
+	ConditionRainShowers, ConditionSnowShowers, ConditionThunderstorm,
+	ConditionHail, ConditionUnknown,
+}
+
+// validateTrayIconConfig checks the tray icon style, colours and palette.
+// The font is checked by validateTrayIconFont when it changes.
+func validateTrayIconConfig(config *TrayIconConfig) error {
+	if _, ok := trayIconRenderers[config.Style]; !ok {
+		return fmt.Errorf("unsupported trayIcon.style: %q", config.Style)
+	}
+
+	switch config.Background {
+	case "", TrayIconBackgroundCircle, TrayIconBackgroundRounded, TrayIconBackgroundNone:
+	default:
+		return fmt.Errorf("unsupported trayIcon.background: %q", config.Background)
+	}
+
+	if config.AnimationFPS < 0 || config.AnimationFPS > maxAnimationFPS {
+		return fmt.Errorf("trayIcon.animationFps must be between 0 and %d", maxAnimationFPS)
+	}
+
+	if config.TextColor != "" {
+		if _, err := parseHexColor(config.TextColor); err != nil {
+			return fmt.Errorf("invalid trayIcon.textColor: %w", err)
+		}
+	}
+
+	if config.ColorScale != nil {
+		if err := validateColorScale(config.ColorScale); err != nil {
+			return err
+		}
+	}
+
+	if config.Palette == nil {
+		return nil
+	}
+
+	for key, value := range config.Palette.Conditions {
+		category := strings.TrimSuffix(key, ".night")
+		if !containsString(conditionCategories, category) {
+			return fmt.Errorf("unknown condition in trayIcon.palette: %q", key)
+		}
+		if _, err := parseHexColor(value); err != nil {
+			return fmt.Errorf("invalid colour for %s in trayIcon.palette: %w", key, err)
+		}
+	}
+
+	for i, band := range config.Palette.TemperatureBands {
+		if i > 0 && band.Min <= config.Palette.TemperatureBands[i-1].Min {
+			return fmt.Errorf("trayIcon.palette temperatureBands must be in ascending order")
+		}
+		if _, err := parseHexColor(band.Color); err != nil {
+			return fmt.Errorf("invalid colour for band %v in trayIcon.palette: %w", band.Min, err)
+		}
+	}
+
+	return nil
+}
+
+// validateTrayIconFont checks that a newly chosen font file can be parsed.
+// An unchanged font is not checked again, so a font that has since been
+// moved does not block unrelated changes; the icon then uses the built-in
+// font.
+func validateTrayIconFont(config *TrayIconConfig, previous string) error {
+	if config.FontFile == "" || config.FontFile == previous {
+		return nil
+	}
+	if _, err := parseFontFile(config.FontFile); err != nil {
+		return fmt.Errorf("invalid trayIcon.fontFile: %w", err)
+	}
+	return nil
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHexColor parses "#rrggbb" or "#rrggbbaa"
+func parseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 && len(hex) != 8 || len(hex) == len(s) {
+		return color.RGBA{}, fmt.Errorf("%q is not a #rrggbb colour", s)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("%q is not a #rrggbb colour", s)
+	}
+
+	// Scale the channels to premultiplied alpha as color.RGBA expects
+	a := uint32(value & 0xff)
+	premultiply := func(c uint32) uint8 { return uint8(c * a / 0xff) }
+	return color.RGBA{
+		R: premultiply(uint32(value >> 24)),
+		G: premultiply(uint32(value >> 16 & 0xff)),
+		B: premultiply(uint32(value >> 8 & 0xff)),
+		A: uint8(a),
+	}, nil
+}
+
+// backgroundColor returns the palette colour for the weather, falling back
+// to the built-in condition colours
+func backgroundColor(weather *WeatherData, config *TrayIconConfig) color.RGBA {
+	palette := config.Palette
+	if palette == nil {
+		return conditionColor(weather)
+	}
+
+	if bands := palette.TemperatureBands; len(bands) > 0 {
+		i := sort.Search(len(bands), func(i int) bool { return bands[i].Min > weather.Temperature })
+		if i > 0 {
+			if c, err := parseHexColor(bands[i-1].Color); err == nil {
+				return c
+			}
+		}
+	}
+
+	category := conditionCategory(weather.WeatherCode)
+	keys := []string{category}
+	if !weather.IsDay {
+		keys = []string{category + ".night", category}
+	}
+	for _, key := range keys {
+		if value, ok := palette.Conditions[key]; ok {
+			if c, err := parseHexColor(value); err == nil {
+				return c
+			}
+		}
+	}
+
+	return conditionColor(weather)
+}
+
+// textColor returns the configured text colour, or one that contrasts with
+// the background
+func textColor(config *TrayIconConfig, background color.RGBA) color.RGBA {
+	if config.TextColor != "" {
+		if c, err := parseHexColor(config.TextColor); err == nil {
+			return c
+		}
+	}
+	return contrastingTextColor(background)
+}
+
+// fillBackground fills img with the configured background shape
+func fillBackground(img *image.RGBA, shape string, c color.Color) {
+	size := float64(img.Bounds().Dx())
+	switch shape {
+	case TrayIconBackgroundNone:
+	case TrayIconBackgroundRounded:
+		fillRoundedRect(img, 0, 0, size, size, size/5, c)
+	default:
+		fillCircle(img, size/2, size/2, size/2, c)
+	}
+}
+
+// fillRoundedRect fills a rounded rectangle with an anti-aliased edge
+func fillRoundedRect(img *image.RGBA, x0, y0, x1, y1, radius float64, c color.Color) {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// Distance from the pixel centre to the rectangle shrunk by radius
+			px := float64(x) + 0.5
+			py := float64(y) + 0.5
+			dx := math.Max(math.Max(x0+radius-px, px-(x1-radius)), 0)
+			dy := math.Max(math.Max(y0+radius-py, py-(y1-radius)), 0)
+			coverage := radius - math.Sqrt(dx*dx+dy*dy) + 0.5
+			if coverage <= 0 {
+				continue
+			}
+			blendPixel(img, x, y, c, coverage)
+		}
+	}
+}
+
+// trayFont creates font faces from a parsed font file
+type trayFont interface {
+	Face(size float64, hinting font.Hinting) (font.Face, error)
+}
+
+// truetypeFont renders TrueType outlines with grid-fitting hinting
+type truetypeFont struct {
+	font *truetype.Font
+}
+
+func (f truetypeFont) Face(size float64, hinting font.Hinting) (font.Face, error) {
+	return truetype.NewFace(f.font, &truetype.Options{Size: size, DPI: 72, Hinting: hinting}), nil
+}
+
+// opentypeFont renders OpenType fonts, including CFF outlines
+type opentypeFont struct {
+	font *opentype.Font
+}
+
+func (f opentypeFont) Face(size float64, hinting font.Hinting) (font.Face, error) {
+	return opentype.NewFace(f.font, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: hinting})
+}
+
+// trayFonts caches parsed fonts by path; "" is the embedded bold font
+var (
+	trayFonts   = make(map[string]trayFont)
+	trayFontsMu sync.Mutex
+)
+
+// loadTrayFont returns the font at path, or the embedded Go bold font when
+// path is empty or the file cannot be used
+func loadTrayFont(path string) (trayFont, error) {
+	trayFontsMu.Lock()
+	defer trayFontsMu.Unlock()
+
+	if ft, ok := trayFonts[path]; ok {
+		return ft, nil
+	}
+
+	if _, ok := trayFonts[""]; !ok {
+		parsed, err := truetype.Parse(gobold.TTF)
+		if err != nil {
+			return nil, err
+		}
+		trayFonts[""] = truetypeFont{parsed}
+	}
+
+	if path == "" {
+		return trayFonts[""], nil
+	}
+
+	ft, err := parseFontFile(path)
+	if err != nil {
+		// Remember the fallback so the error is only logged once
+		log.Printf("Failed to load tray icon font, using the embedded font: %v", err)
+		ft = trayFonts[""]
+	}
+	trayFonts[path] = ft
+	return ft, nil
+}
+
+// parseFontFile parses a TTF or OTF file. TrueType outlines use the
+// truetype package for its hinting; other fonts use the OpenType parser.
+func parseFontFile(path string) (trayFont, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed, err := truetype.Parse(data); err == nil {
+		return truetypeFont{parsed}, nil
+	}
+
+	parsed, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a TTF or OTF font: %w", path, err)
+	}
+	return opentypeFont{parsed}, nil
+}
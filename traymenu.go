@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// trayMenuActions are the handlers behind the tray menu commands
+type trayMenuActions struct {
+	Show            func()
+	Refresh         func()
+	ProfileSwitched func()
+	About           func()
+	Quit            func()
+}
+
+// trayMenu builds the tray menu: a summary of the current weather, the
+// forecast and the app commands. It is rebuilt for every weather update.
+type trayMenu struct {
+	app     *App
+	menu    *application.Menu
+	actions trayMenuActions
+
+	mu              sync.Mutex
+	weather         *WeatherData
+	alertActive     bool
+	lastUpdatedItem *application.MenuItem
+	acknowledgeItem *application.MenuItem
+}
+
+// newTrayMenu creates the tray menu without weather information
+func newTrayMenu(app *App, menu *application.Menu, actions trayMenuActions) *trayMenu {
+	m := &trayMenu{app: app, menu: menu, actions: actions}
+	m.rebuild()
+	return m
+}
+
+// SetWeather rebuilds the menu for new weather data
+func (m *trayMenu) SetWeather(weather *WeatherData) {
+	m.mu.Lock()
+	m.weather = weather
+	m.mu.Unlock()
+
+	m.Rebuild()
+}
+
+// SetAlert enables the acknowledge item while an alert is unacknowledged
+func (m *trayMenu) SetAlert(active bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.alertActive = active
+	if m.acknowledgeItem != nil {
+		m.acknowledgeItem.SetEnabled(active)
+		m.menu.Update()
+	}
+}
+
+// Rebuild recreates every menu item, e.g. after profiles or the language
+// have changed
+func (m *trayMenu) Rebuild() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rebuild()
+	m.menu.Update()
+}
+
+// rebuild fills the menu. The caller must hold m.mu.
+func (m *trayMenu) rebuild() {
+	config, err := m.app.LoadConfig()
+	if err != nil {
+		log.Printf("Failed to load config: %v", err)
+		config = m.app.GetDefaultConfig()
+	}
+	lang := config.Language
+
+	m.menu.Clear()
+	m.lastUpdatedItem = nil
+
+	if weather := m.weather; weather != nil {
+		unit := temperatureSymbol(temperatureUnit(config))
+		m.menu.Add(fmt.Sprintf("%s, %s%s", weather.Condition,
+			formatTemperature(weather.Temperature, config), unit)).SetEnabled(false)
+		m.menu.Add(fmt.Sprintf("%s: %s%s", translate(lang, "ui.feelsLike"),
+			formatTemperature(weather.FeelsLike, config), unit)).SetEnabled(false)
+		m.menu.Add(fmt.Sprintf("%s: %d%%", translate(lang, "ui.humidity"),
+			weather.Humidity)).SetEnabled(false)
+		m.menu.Add(fmt.Sprintf("%s: %s km/h", translate(lang, "ui.windSpeed"),
+			formatNumber(weather.WindSpeed, 0, lang))).SetEnabled(false)
+
+		forecastMenu := m.menu.AddSubmenu(translate(lang, "ui.forecast"))
+		for _, day := range weather.Forecast {
+			forecastMenu.Add(fmt.Sprintf("%s: %s%s / %s%s, %s", day.DayOfWeek,
+				formatTemperature(day.MaxTemp, config), unit,
+				formatTemperature(day.MinTemp, config), unit, day.Condition)).SetEnabled(false)
+		}
+
+		m.lastUpdatedItem = m.menu.Add(lastUpdatedLabel(weather, lang, time.Now())).SetEnabled(false)
+		m.menu.AddSeparator()
+	}
+
+	m.menu.Add(translate(lang, "ui.showWeather")).OnClick(func(ctx *application.Context) {
+		m.actions.Show()
+	})
+	m.menu.Add(translate(lang, "ui.refreshWeather")).OnClick(func(ctx *application.Context) {
+		m.actions.Refresh()
+	})
+	profilesMenu := m.menu.AddSubmenu(translate(lang, "ui.profiles"))
+	populateProfilesMenu(profilesMenu, m.app, m.actions.ProfileSwitched)
+	m.acknowledgeItem = m.menu.Add(translate(lang, "ui.acknowledgeAlert")).OnClick(func(ctx *application.Context) {
+		m.app.AcknowledgeAlert()
+	}).SetEnabled(m.alertActive)
+	m.menu.AddSeparator()
+	m.menu.Add(translate(lang, "ui.about")).OnClick(func(ctx *application.Context) {
+		m.actions.About()
+	})
+	m.menu.Add(translate(lang, "ui.quit")).OnClick(func(ctx *application.Context) {
+		m.actions.Quit()
+	})
+}
+
+// populateProfilesMenu fills the tray profiles submenu with one radio item
+// per saved profile. onSwitch is called after a profile has been selected.
+func populateProfilesMenu(profilesMenu *application.Menu, a *App, onSwitch func()) {
+	profilesMenu.Clear()
+
+	profiles, err := a.ListProfiles()
+	if err != nil {
+		log.Printf("Failed to list profiles: %v", err)
+	}
+	active, _ := a.GetActiveProfile()
+
+	if len(profiles) == 0 {
+		profilesMenu.Add(translate(a.currentLanguage(), "ui.noProfiles")).SetEnabled(false)
+		return
+	}
+
+	for _, name := range profiles {
+		profileName := name
+		profilesMenu.AddRadio(profileName, profileName == active).OnClick(func(ctx *application.Context) {
+			if err := a.SwitchProfile(profileName); err != nil {
+				log.Printf("Failed to switch profile: %v", err)
+				return
+			}
+			onSwitch()
+		})
+	}
+}
+
+// RefreshLastUpdated updates the relative time of the last update
+func (m *trayMenu) RefreshLastUpdated() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lastUpdatedItem == nil || m.weather == nil {
+		return
+	}
+	m.lastUpdatedItem.SetLabel(lastUpdatedLabel(m.weather, m.app.currentLanguage(), time.Now()))
+	m.menu.Update()
+}
+
+// lastUpdatedLabel describes how long ago the weather was fetched
+func lastUpdatedLabel(weather *WeatherData, lang string, now time.Time) string {
+	updated, err := time.ParseInLocation("2006-01-02 15:04:05", weather.LastUpdated, time.Local)
+	if err != nil {
+		return translate(lang, "ui.lastUpdated", weather.LastUpdated)
+	}
+
+	return translate(lang, "ui.lastUpdated", relativeTime(now.Sub(updated), lang))
+}
+
+// relativeTime formats a duration in the past, e.g. "5 min ago"
+func relativeTime(elapsed time.Duration, lang string) string {
+	switch {
+	case elapsed < time.Minute:
+		return translate(lang, "time.justNow")
+	case elapsed < time.Hour:
+		return translate(lang, "time.minutesAgo", int(elapsed/time.Minute))
+	case elapsed < 24*time.Hour:
+		return translate(lang, "time.hoursAgo", int(elapsed/time.Hour))
+	default:
+		return translate(lang, "time.daysAgo", int(elapsed/(24*time.Hour)))
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// updateCheckFileName stores the result of the last update check next to
+// config.json
+const updateCheckFileName = "update-check.json"
+
+// updateSchedulerInterval is how often the background checker wakes up to
+// see whether a check is due
+const updateSchedulerInterval = time.Hour
+
+// updateCheckMu serialises access to the update check file
+var updateCheckMu sync.Mutex
+
+// UpdateCheckResult is the outcome of the last update check
+type UpdateCheckResult struct {
+	CheckedAt time.Time   `json:"checkedAt"`
+	Info      *UpdateInfo `json:"info,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// getUpdateCheckPath returns the path of the update check file
+func (a *App) getUpdateCheckPath() (string, error) {
+	configPath, err := a.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), updateCheckFileName), nil
+}
+
+// GetLastUpdateCheck returns the result of the last update check, or nil
+// if updates were never checked
+func (a *App) GetLastUpdateCheck() (*UpdateCheckResult, error) {
+	updateCheckMu.Lock()
+	defer updateCheckMu.Unlock()
+
+	path, err := a.getUpdateCheckPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result UpdateCheckResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// recordUpdateCheck persists the outcome of an update check
+func (a *App) recordUpdateCheck(info *UpdateInfo, checkErr error) {
+	result := UpdateCheckResult{CheckedAt: time.Now(), Info: info}
+	if checkErr != nil {
+		result.Error = checkErr.Error()
+	}
+
+	updateCheckMu.Lock()
+	defer updateCheckMu.Unlock()
+
+	path, err := a.getUpdateCheckPath()
+	if err != nil {
+		log.Printf("Failed to record update check: %v", err)
+		return
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		log.Printf("Failed to record update check: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		log.Printf("Failed to record update check: %v", err)
+	}
+}
+
+// updateCheckInterval returns the configured time between background
+// checks
+func updateCheckInterval(config *UpdateConfig) time.Duration {
+	if config.CheckIntervalHours > 0 {
+		return time.Duration(config.CheckIntervalHours) * time.Hour
+	}
+	return CheckInterval
+}
+
+// updateCheckDue reports whether the last check is older than interval. A
+// check dated in the future, e.g. after the clock was changed, is treated
+// as due.
+func updateCheckDue(last *UpdateCheckResult, now time.Time, interval time.Duration) bool {
+	if last == nil {
+		return true
+	}
+	elapsed := now.Sub(last.CheckedAt)
+	return elapsed < 0 || elapsed >= interval
+}
+
+// StartUpdateChecks checks for updates in the background whenever the last
+// check is older than the configured interval, including across restarts,
+// and calls notify when an update is available. The configuration is read
+// on every wake-up, so interval changes apply without a restart.
+func (a *App) StartUpdateChecks(notify func(info *UpdateInfo)) {
+	go func() {
+		ticker := time.NewTicker(updateSchedulerInterval)
+		defer ticker.Stop()
+		for {
+			a.runScheduledUpdateCheck(notify)
+			<-ticker.C
+		}
+	}()
+}
+
+// runScheduledUpdateCheck checks for updates if enabled and due
+func (a *App) runScheduledUpdateCheck(notify func(info *UpdateInfo)) {
+	config, err := a.LoadConfig()
+	if err != nil || !config.Updates.AutoCheck {
+		return
+	}
+
+	last, err := a.GetLastUpdateCheck()
+	if err != nil {
+		log.Printf("Failed to read last update check: %v", err)
+	}
+	if !updateCheckDue(last, time.Now(), updateCheckInterval(&config.Updates)) {
+		return
+	}
+
+	info, err := a.CheckForUpdates()
+	if err != nil {
+		log.Printf("Background update check failed: %v", err)
+		return
+	}
+	if info.Available {
+		notify(info)
+	}
+}
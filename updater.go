@@ -0,0 +1,648 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// Update phases reported in UpdateProgress
+const (
+	UpdatePhaseDownloading = "downloading"
+	UpdatePhaseVerifying   = "verifying"
+	UpdatePhaseInstalling  = "installing"
+	UpdatePhaseRestarting  = "restarting"
+)
+
+// UpdateProgress is emitted as the "updateProgress" event while an update
+// is installed
+type UpdateProgress struct {
+	Phase      string `json:"phase"`
+	Downloaded int64  `json:"downloaded"`
+	Total      int64  `json:"total"` // -1 when the size is unknown
+}
+
+// maxChecksumsSize limits how much of a checksums or signature file is read
+const maxChecksumsSize = 1 << 20
+
+// updateHealthEnv passes the updated app the file to create once it has
+// started, so the previous version can be restored if it never does
+const updateHealthEnv = envPrefix + "UPDATE_HEALTH_FILE"
+
+// updateHealthTimeout is how long the updated app has to report that it
+// started
+const updateHealthTimeout = 30 * time.Second
+
+// errInstallerLaunched reports that the platform installer took over, so
+// the app should quit instead of restarting itself
+var errInstallerLaunched = errors.New("installer launched")
+
+// updater downloads, verifies and installs release assets
+type updater struct {
+	client  *http.Client
+	exePath string
+	// args are passed to the updated app when it is restarted
+	args []string
+	// stagingDir holds downloads and installers until they are installed
+	stagingDir    string
+	healthTimeout time.Duration
+	progress      func(UpdateProgress)
+}
+
+// newUpdater creates an updater for the running executable
+func newUpdater(progress func(UpdateProgress)) (*updater, error) {
+	exePath, err := installedExecutable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	stagingDir, err := updateStagingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the update directory: %w", err)
+	}
+
+	return &updater{
+		client:        newHTTPClient(30 * time.Minute),
+		exePath:       exePath,
+		args:          restartArgs(os.Args[1:]),
+		stagingDir:    stagingDir,
+		healthTimeout: updateHealthTimeout,
+		progress:      progress,
+	}, nil
+}
+
+// restartArgs returns the launch arguments for the updated app without
+// --launch-delay and --minimized, so it starts at once and is visible, and
+// reports its health before the rollback timeout
+func restartArgs(args []string) []string {
+	var kept []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' {
+			// Flag parsing stops here, so the rest is kept as it is
+			return append(kept, args[i:]...)
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		switch name {
+		case "minimized":
+		case "launch-delay":
+			if !hasValue {
+				i++
+			}
+		default:
+			// Every other flag takes a value
+			kept = append(kept, arg)
+			if !hasValue && i+1 < len(args) {
+				i++
+				kept = append(kept, args[i])
+			}
+		}
+	}
+	return kept
+}
+
+// updateStagingDir returns the directory in the user cache that downloads
+// are staged in
+func updateStagingDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(cacheDir, "myWeatherApp", "updates")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// installedExecutable returns the file an update replaces
+func installedExecutable() (string, error) {
+	// An AppImage runs from a temporary mount; replace the image itself
+	if appImage := os.Getenv("APPIMAGE"); appImage != "" {
+		return appImage, nil
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exePath)
+}
+
+// InstallUpdate downloads the update found by CheckForUpdates, verifies its
+// signed SHA-256 checksum, replaces the running binary and restarts the app. Progress
+// is reported through the "updateProgress" event.
+func (a *App) InstallUpdate() error {
+	info, err := a.CheckForUpdates()
+	if err != nil {
+		return err
+	}
+	if !info.Available {
+		return fmt.Errorf("no update available")
+	}
+	if info.Error != "" {
+		return fmt.Errorf("cannot install %s: %s", info.Version, info.Error)
+	}
+
+	u, err := newUpdater(a.emitUpdateProgress)
+	if err != nil {
+		return err
+	}
+
+	if err := u.install(info); err != nil {
+		if errors.Is(err, errInstallerLaunched) {
+			application.Get().Quit()
+			return nil
+		}
+		return err
+	}
+
+	u.report(UpdatePhaseRestarting, 0, 0)
+	if err := u.restart(); err != nil {
+		return err
+	}
+	application.Get().Quit()
+	return nil
+}
+
+// emitUpdateProgress sends update progress to the frontend
+func (a *App) emitUpdateProgress(progress UpdateProgress) {
+	if app := application.Get(); app != nil {
+		app.Event.Emit("updateProgress", &progress)
+	}
+}
+
+// report sends a progress update if a progress function is set
+func (u *updater) report(phase string, downloaded, total int64) {
+	if u.progress != nil {
+		u.progress(UpdateProgress{Phase: phase, Downloaded: downloaded, Total: total})
+	}
+}
+
+// install downloads and verifies the asset described by info, then installs
+// it. The download is staged in the user cache, which may be on another
+// filesystem than the executable.
+func (u *updater) install(info *UpdateInfo) error {
+	if info.DownloadURL == "" {
+		return fmt.Errorf("no download for %s/%s in release %s", runtime.GOOS, runtime.GOARCH, info.Version)
+	}
+	checksums, err := u.verifiedChecksums(info)
+	if err != nil {
+		return err
+	}
+	expected, ok := checksums[info.AssetName]
+	if !ok {
+		return fmt.Errorf("checksums file has no entry for %s", info.AssetName)
+	}
+
+	dir, err := os.MkdirTemp(u.stagingDir, "update-")
+	if err != nil {
+		return fmt.Errorf("failed to create the update directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	assetPath := filepath.Join(dir, filepath.Base(info.AssetName))
+	sum, err := u.download(info.DownloadURL, assetPath)
+	if err != nil {
+		return err
+	}
+
+	u.report(UpdatePhaseVerifying, 0, 0)
+	if !strings.EqualFold(sum, expected) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", info.AssetName, expected, sum)
+	}
+
+	u.report(UpdatePhaseInstalling, 0, 0)
+	name := strings.ToLower(info.AssetName)
+	if format := assetFormat(name); isInstallerFormat(format) {
+		return u.launchInstaller(assetPath, format)
+	}
+
+	newPath := filepath.Join(dir, "new-binary")
+	switch {
+	case strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz"):
+		err = u.extractTarGz(assetPath, newPath)
+	case strings.HasSuffix(name, ".zip"):
+		err = u.extractZip(assetPath, newPath)
+	default:
+		newPath = assetPath
+	}
+	if err != nil {
+		return err
+	}
+
+	return u.replaceBinary(newPath)
+}
+
+// download saves url to dest and returns the SHA-256 of its contents
+func (u *updater) download(url, dest string) (string, error) {
+	body, size, err := fetchURL(u.client, url)
+	if err != nil {
+		return "", fmt.Errorf("failed to download update: %w", err)
+	}
+	defer body.Close()
+
+	file, err := os.Create(dest)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	counter := &progressWriter{total: size, report: func(downloaded, total int64) {
+		u.report(UpdatePhaseDownloading, downloaded, total)
+	}}
+	if _, err := io.Copy(io.MultiWriter(file, hash, counter), body); err != nil {
+		return "", fmt.Errorf("failed to download update: %w", err)
+	}
+	counter.flush()
+
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// progressWriter counts bytes and reports progress at most every 100ms
+type progressWriter struct {
+	downloaded int64
+	total      int64
+	reported   int64
+	last       time.Time
+	report     func(downloaded, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.downloaded += int64(len(b))
+	if time.Since(p.last) >= 100*time.Millisecond {
+		p.flush()
+	}
+	return len(b), nil
+}
+
+// flush reports the current count unless it was already reported
+func (p *progressWriter) flush() {
+	if !p.last.IsZero() && p.reported == p.downloaded {
+		return
+	}
+	p.last = time.Now()
+	p.reported = p.downloaded
+	p.report(p.downloaded, p.total)
+}
+
+// verifiedChecksums downloads the release checksums and their signature
+// and returns the checksums once the signature is verified
+func (u *updater) verifiedChecksums(info *UpdateInfo) (map[string]string, error) {
+	if info.ChecksumsURL == "" {
+		return nil, fmt.Errorf("release %s has no checksums file", info.Version)
+	}
+	if info.SignatureURL == "" {
+		return nil, fmt.Errorf("release %s is not signed", info.Version)
+	}
+
+	checksums, err := u.fetchSmallFile(info.ChecksumsURL, "checksums")
+	if err != nil {
+		return nil, err
+	}
+	signature, err := u.fetchSmallFile(info.SignatureURL, "signature")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyChecksumsSignature(checksums, signature, info.Version); err != nil {
+		return nil, err
+	}
+
+	return parseChecksums(checksums)
+}
+
+// fetchSmallFile downloads a checksums or signature file
+func (u *updater) fetchSmallFile(url, what string) ([]byte, error) {
+	body, _, err := fetchURL(u.client, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", what, err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxChecksumsSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", what, err)
+	}
+
+	return data, nil
+}
+
+// parseChecksums parses "<sha256>  <name>" lines as written by sha256sum;
+// a "*" before the name marks binary mode and is ignored
+func parseChecksums(data []byte) (map[string]string, error) {
+	checksums := make(map[string]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed checksums line: %q", line)
+		}
+		sum, name := fields[0], strings.TrimPrefix(fields[1], "*")
+		if decoded, err := hex.DecodeString(sum); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("malformed checksum for %s", name)
+		}
+		checksums[name] = strings.ToLower(sum)
+	}
+
+	return checksums, scanner.Err()
+}
+
+// binaryName returns the executable name to look for in archives
+func (u *updater) binaryName() string {
+	return filepath.Base(u.exePath)
+}
+
+// extractTarGz extracts the executable from a .tar.gz archive to dest
+func (u *updater) extractTarGz(archive, dest string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read update archive: %w", err)
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read update archive: %w", err)
+		}
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == u.binaryName() {
+			return writeExecutable(dest, reader)
+		}
+	}
+
+	return fmt.Errorf("update archive does not contain %s", u.binaryName())
+}
+
+// extractZip extracts the executable from a .zip archive to dest
+func (u *updater) extractZip(archive, dest string) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("failed to read update archive: %w", err)
+	}
+	defer reader.Close()
+
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() || filepath.Base(entry.Name) != u.binaryName() {
+			continue
+		}
+		src, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read update archive: %w", err)
+		}
+		defer src.Close()
+		return writeExecutable(dest, src)
+	}
+
+	return fmt.Errorf("update archive does not contain %s", u.binaryName())
+}
+
+// writeExecutable copies src to a new executable file at dest
+func writeExecutable(dest string, src io.Reader) error {
+	file, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, src); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to extract update: %w", err)
+	}
+	return file.Close()
+}
+
+// moveFile moves src to dest, copying it when a rename is not possible,
+// e.g. across filesystems
+func moveFile(src, dest string, perm os.FileMode) error {
+	if err := os.Rename(src, dest); err == nil {
+		return os.Chmod(dest, perm)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dest)
+		return err
+	}
+
+	in.Close()
+	return os.Remove(src)
+}
+
+// oldBinaryPath is where the replaced binary is kept until the next launch
+func (u *updater) oldBinaryPath() string {
+	return u.exePath + ".old"
+}
+
+// replaceBinary swaps newPath in for the running executable. The new binary
+// is first moved next to the executable so the swap is a rename on one
+// filesystem. The running binary is renamed rather than overwritten, which
+// Windows allows, and is restored if the swap fails.
+func (u *updater) replaceBinary(newPath string) error {
+	stagedPath := u.exePath + ".new"
+	if err := moveFile(newPath, stagedPath, 0755); err != nil {
+		os.Remove(stagedPath)
+		return fmt.Errorf("cannot write to the application directory: %w", err)
+	}
+
+	oldPath := u.oldBinaryPath()
+	os.Remove(oldPath)
+	if err := os.Rename(u.exePath, oldPath); err != nil {
+		os.Remove(stagedPath)
+		return fmt.Errorf("failed to move the current binary aside: %w", err)
+	}
+
+	if err := os.Rename(stagedPath, u.exePath); err != nil {
+		os.Remove(stagedPath)
+		if rollbackErr := os.Rename(oldPath, u.exePath); rollbackErr != nil {
+			return fmt.Errorf("failed to install update: %v; rollback also failed: %w", err, rollbackErr)
+		}
+		return fmt.Errorf("failed to install update: %w", err)
+	}
+
+	return nil
+}
+
+// rollback restores the binary replaced by replaceBinary
+func (u *updater) rollback() error {
+	if err := os.Rename(u.oldBinaryPath(), u.exePath); err != nil {
+		return fmt.Errorf("failed to restore the previous version: %w", err)
+	}
+	return nil
+}
+
+// restart starts the new binary with the current arguments and waits for it
+// to report that it started. If it cannot be started, exits first or does
+// not report in time, it is stopped and the previous binary is restored.
+func (u *updater) restart() error {
+	healthPath := filepath.Join(u.stagingDir, fmt.Sprintf("health-%d", os.Getpid()))
+	os.Remove(healthPath)
+	defer os.Remove(healthPath)
+
+	cmd := exec.Command(u.exePath, u.args...)
+	cmd.Env = append(os.Environ(), updateHealthEnv+"="+healthPath)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		if rollbackErr := u.rollback(); rollbackErr != nil {
+			return fmt.Errorf("failed to start the updated app: %v; %w", err, rollbackErr)
+		}
+		return fmt.Errorf("failed to start the updated app, the previous version was restored: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	if err := waitForHealth(healthPath, exited, u.healthTimeout); err != nil {
+		// The binary cannot be replaced on Windows while it runs
+		if cmd.Process.Kill() == nil {
+			<-exited
+		}
+		if rollbackErr := u.rollback(); rollbackErr != nil {
+			return fmt.Errorf("%v; %w", err, rollbackErr)
+		}
+		return fmt.Errorf("%w, the previous version was restored", err)
+	}
+
+	return nil
+}
+
+// waitForHealth waits until the updated app creates healthPath. It fails if
+// the app exits first or timeout passes.
+func waitForHealth(healthPath string, exited <-chan error, timeout time.Duration) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+
+	for {
+		select {
+		case err := <-exited:
+			// It may have reported just before exiting
+			if _, statErr := os.Stat(healthPath); statErr == nil {
+				return nil
+			}
+			if err == nil {
+				return fmt.Errorf("the updated app exited during startup")
+			}
+			return fmt.Errorf("the updated app exited during startup: %v", err)
+		case <-deadline:
+			return fmt.Errorf("the updated app did not start within %s", timeout)
+		case <-ticker.C:
+			if _, err := os.Stat(healthPath); err == nil {
+				return nil
+			}
+		}
+	}
+}
+
+// updateHealthPath returns the file an app started by an update creates
+// once it has started, or "" for a normal launch. The variable is cleared
+// so it is not passed on to processes the app starts.
+func updateHealthPath() string {
+	path := os.Getenv(updateHealthEnv)
+	os.Unsetenv(updateHealthEnv)
+	return path
+}
+
+// reportUpdateHealth tells the app that installed the update that this
+// version started
+func reportUpdateHealth(path string) {
+	if err := os.WriteFile(path, []byte(currentVersion()), 0600); err != nil {
+		log.Printf("Failed to report a successful update: %v", err)
+	}
+}
+
+// launchInstaller opens an installer package with the platform handler.
+// Windows setup programs are run directly.
+func (u *updater) launchInstaller(path, format string) error {
+	// Keep the installer after the download directory is removed
+	dest := filepath.Join(u.stagingDir, filepath.Base(path))
+	if err := moveFile(path, dest, 0644); err != nil {
+		return fmt.Errorf("failed to prepare installer: %w", err)
+	}
+
+	var cmd *exec.Cmd
+	switch {
+	case format == AssetFormatSetup:
+		cmd = exec.Command(dest)
+	case runtime.GOOS == "windows":
+		cmd = exec.Command("msiexec", "/i", dest)
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("open", dest)
+	default:
+		cmd = exec.Command("xdg-open", dest)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to launch installer: %w", err)
+	}
+	cmd.Process.Release()
+
+	return errInstallerLaunched
+}
+
+// cleanupPreviousUpdate removes the binary left behind by the last update.
+// It is kept while an update is being checked, so it can be restored.
+func cleanupPreviousUpdate() {
+	exePath, err := installedExecutable()
+	if err != nil {
+		return
+	}
+
+	err = os.Remove(exePath + ".old")
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to remove previous version: %v", err)
+	}
+}
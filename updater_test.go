@@ -0,0 +1,425 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// updaterHelperEnv selects how TestUpdaterHelperProcess behaves when the
+// test binary is started as the updated app
+const updaterHelperEnv = "UPDATER_TEST_HELPER"
+
+// testSigningKey is a minisign key pair for signing test releases
+type testSigningKey struct {
+	id      [8]byte
+	public  ed25519.PublicKey
+	private ed25519.PrivateKey
+}
+
+// newTestSigningKey creates a key pair and makes it the embedded update key
+// for the test
+func newTestSigningKey(t *testing.T) *testSigningKey {
+	t.Helper()
+	key := generateTestSigningKey(t)
+
+	previous := updatePublicKey
+	updatePublicKey = key.publicKeyString()
+	t.Cleanup(func() { updatePublicKey = previous })
+
+	return key
+}
+
+// generateTestSigningKey creates a key pair
+func generateTestSigningKey(t *testing.T) *testSigningKey {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &testSigningKey{public: public, private: private}
+	rand.Read(key.id[:])
+	return key
+}
+
+// publicKeyString returns the key in the form printed by "minisign -G"
+func (k *testSigningKey) publicKeyString() string {
+	data := append([]byte(minisignAlgorithmLegacy), k.id[:]...)
+	return base64.StdEncoding.EncodeToString(append(data, k.public...))
+}
+
+// testReleaseVersion is the version test releases are published as
+const testReleaseVersion = "v9.9.9"
+
+// sign returns a minisign signature file for message, with version in the
+// trusted comment as the release workflow writes it
+func (k *testSigningKey) sign(message []byte, version string) []byte {
+	signature := ed25519.Sign(k.private, message)
+	trustedComment := "timestamp:1760000000 file:SHA256SUMS"
+	if version != "" {
+		trustedComment += " version:" + version
+	}
+	global := ed25519.Sign(k.private, append(bytes.Clone(signature), trustedComment...))
+
+	data := append([]byte(minisignAlgorithmLegacy), k.id[:]...)
+	return []byte(fmt.Sprintf("untrusted comment: test signature\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(append(data, signature...)),
+		trustedComment,
+		base64.StdEncoding.EncodeToString(global)))
+}
+
+// testRelease serves an asset with its checksums and signature
+type testRelease struct {
+	asset     []byte
+	checksums []byte
+	signature []byte
+}
+
+// newTestRelease returns a release of asset named name, signed with key
+func newTestRelease(name string, asset []byte, key *testSigningKey) *testRelease {
+	sum := sha256.Sum256(asset)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  " + name + "\n")
+	return &testRelease{asset: asset, checksums: checksums, signature: key.sign(checksums, testReleaseVersion)}
+}
+
+// serve starts an https server for the release and returns the update info
+// pointing at it
+func (r *testRelease) serve(t *testing.T, name string) (*UpdateInfo, *http.Client) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+name, func(w http.ResponseWriter, req *http.Request) {
+		w.Write(r.asset)
+	})
+	mux.HandleFunc("/checksums.txt", func(w http.ResponseWriter, req *http.Request) {
+		w.Write(r.checksums)
+	})
+	mux.HandleFunc("/checksums.txt.minisig", func(w http.ResponseWriter, req *http.Request) {
+		w.Write(r.signature)
+	})
+
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	return &UpdateInfo{
+		Version:      testReleaseVersion,
+		Available:    true,
+		DownloadURL:  server.URL + "/" + name,
+		AssetName:    name,
+		ChecksumsURL: server.URL + "/checksums.txt",
+		SignatureURL: server.URL + "/checksums.txt.minisig",
+	}, server.Client()
+}
+
+// newTestUpdater returns an updater for a fake installed executable
+func newTestUpdater(t *testing.T, client *http.Client, contents []byte) *updater {
+	t.Helper()
+	name := "myWeatherApp"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	exePath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(exePath, contents, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	return &updater{
+		client:        client,
+		exePath:       exePath,
+		stagingDir:    t.TempDir(),
+		healthTimeout: 5 * time.Second,
+	}
+}
+
+// assertFileContents fails unless path holds want
+func assertFileContents(t *testing.T, path string, want []byte) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s = %q, want %q", filepath.Base(path), got, want)
+	}
+}
+
+func TestUpdaterInstall(t *testing.T) {
+	key := newTestSigningKey(t)
+	name := "myWeatherApp-linux-amd64"
+	info, client := newTestRelease(name, []byte("new version"), key).serve(t, name)
+
+	var phases []string
+	u := newTestUpdater(t, client, []byte("old version"))
+	u.progress = func(progress UpdateProgress) {
+		if len(phases) == 0 || phases[len(phases)-1] != progress.Phase {
+			phases = append(phases, progress.Phase)
+		}
+	}
+
+	if err := u.install(info); err != nil {
+		t.Fatal(err)
+	}
+
+	assertFileContents(t, u.exePath, []byte("new version"))
+	assertFileContents(t, u.oldBinaryPath(), []byte("old version"))
+	if want := []string{UpdatePhaseDownloading, UpdatePhaseVerifying, UpdatePhaseInstalling}; strings.Join(phases, ",") != strings.Join(want, ",") {
+		t.Errorf("phases = %v, want %v", phases, want)
+	}
+
+	// Nothing is left behind in the staging or application directory
+	if entries, _ := os.ReadDir(u.stagingDir); len(entries) != 0 {
+		t.Errorf("staging directory not cleaned up: %v", entries)
+	}
+	if _, err := os.Stat(u.exePath + ".new"); !os.IsNotExist(err) {
+		t.Error("staged binary left next to the executable")
+	}
+}
+
+func TestUpdaterRejectsBadReleases(t *testing.T) {
+	name := "myWeatherApp-linux-amd64"
+	key := newTestSigningKey(t)
+	otherKey := generateTestSigningKey(t)
+
+	tests := []struct {
+		name   string
+		modify func(r *testRelease)
+		want   string
+	}{
+		{"checksum mismatch", func(r *testRelease) {
+			r.asset = []byte("tampered version")
+		}, "checksum mismatch"},
+		{"signed with another key", func(r *testRelease) {
+			r.signature = otherKey.sign(r.checksums, testReleaseVersion)
+		}, "signed with key"},
+		{"signed for another version", func(r *testRelease) {
+			r.signature = key.sign(r.checksums, "v1.0.0")
+		}, "signed for v1.0.0"},
+		{"signed without a version", func(r *testRelease) {
+			r.signature = key.sign(r.checksums, "")
+		}, "does not name a version"},
+		{"checksums changed after signing", func(r *testRelease) {
+			sum := sha256.Sum256([]byte("tampered version"))
+			r.checksums = []byte(hex.EncodeToString(sum[:]) + "  " + name + "\n")
+		}, "signature does not match"},
+		{"malformed signature", func(r *testRelease) {
+			r.signature = []byte("not a signature")
+		}, "invalid minisign signature"},
+		{"asset missing from checksums", func(r *testRelease) {
+			r.checksums = []byte(strings.Repeat("0", 64) + "  other-asset\n")
+			r.signature = key.sign(r.checksums, testReleaseVersion)
+		}, "no entry for"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := newTestRelease(name, []byte("new version"), key)
+			tt.modify(release)
+			info, client := release.serve(t, name)
+
+			u := newTestUpdater(t, client, []byte("old version"))
+			err := u.install(info)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("install error = %v, want it to contain %q", err, tt.want)
+			}
+
+			assertFileContents(t, u.exePath, []byte("old version"))
+			if _, err := os.Stat(u.oldBinaryPath()); !os.IsNotExist(err) {
+				t.Error("binary moved aside although the update was rejected")
+			}
+		})
+	}
+}
+
+func TestUpdaterRejectsUnsignedBuild(t *testing.T) {
+	key := generateTestSigningKey(t)
+	previous := updatePublicKey
+	updatePublicKey = ""
+	t.Cleanup(func() { updatePublicKey = previous })
+
+	name := "myWeatherApp-linux-amd64"
+	info, client := newTestRelease(name, []byte("new version"), key).serve(t, name)
+	u := newTestUpdater(t, client, []byte("old version"))
+	if err := u.install(info); err == nil || !strings.Contains(err.Error(), "no update signing key") {
+		t.Fatalf("install error = %v, want a missing key error", err)
+	}
+}
+
+func TestUpdaterInstallFromArchive(t *testing.T) {
+	key := newTestSigningKey(t)
+	u := newTestUpdater(t, nil, []byte("old version"))
+	archive := zipArchive(t, map[string][]byte{
+		"README.md": []byte("readme"),
+		"myWeatherApp/" + filepath.Base(u.exePath): []byte("new version"),
+	})
+
+	name := "myWeatherApp-windows-amd64.zip"
+	info, client := newTestRelease(name, archive, key).serve(t, name)
+	u.client = client
+
+	if err := u.install(info); err != nil {
+		t.Fatal(err)
+	}
+	assertFileContents(t, u.exePath, []byte("new version"))
+}
+
+func TestMoveFile(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	if err := os.WriteFile(src, []byte("contents"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "dest")
+	if err := moveFile(src, dest, 0755); err != nil {
+		t.Fatal(err)
+	}
+	assertFileContents(t, dest, []byte("contents"))
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("source still exists after the move")
+	}
+
+	// A failed copy leaves the source in place
+	if err := moveFile(dest, filepath.Join(t.TempDir(), "missing", "dest"), 0755); err == nil {
+		t.Error("move into a missing directory succeeded")
+	}
+	assertFileContents(t, dest, []byte("contents"))
+}
+
+// TestUpdaterHelperProcess is started by the restart tests as the updated
+// app. It does nothing in a normal test run.
+func TestUpdaterHelperProcess(t *testing.T) {
+	switch os.Getenv(updaterHelperEnv) {
+	case "healthy":
+		reportUpdateHealth(updateHealthPath())
+		os.Exit(0)
+	case "crash":
+		os.Exit(3)
+	case "hang":
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+}
+
+func TestUpdaterRestart(t *testing.T) {
+	testBinary, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot locate the test binary:", err)
+	}
+	newBinary, err := os.ReadFile(testBinary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		mode     string
+		rollback bool
+	}{
+		{"healthy", false},
+		{"crash", true},
+		{"hang", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			t.Setenv(updaterHelperEnv, tt.mode)
+
+			u := newTestUpdater(t, nil, []byte("old version"))
+			u.args = []string{"-test.run=^TestUpdaterHelperProcess$"}
+			u.healthTimeout = 2 * time.Second
+
+			newPath := filepath.Join(t.TempDir(), "new-binary")
+			if err := os.WriteFile(newPath, newBinary, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := u.replaceBinary(newPath); err != nil {
+				t.Fatal(err)
+			}
+
+			err := u.restart()
+			if tt.rollback {
+				if err == nil || !strings.Contains(err.Error(), "previous version was restored") {
+					t.Fatalf("restart error = %v, want a rollback", err)
+				}
+				assertFileContents(t, u.exePath, []byte("old version"))
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			installed, err := os.ReadFile(u.exePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(installed, newBinary) {
+				t.Error("updated binary was not kept")
+			}
+			// The previous version is kept until the next launch
+			assertFileContents(t, u.oldBinaryPath(), []byte("old version"))
+		})
+	}
+}
+
+// zipArchive returns a zip file with the given files
+func zipArchive(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, contents := range files {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.Copy(w, bytes.NewReader(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRestartArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{nil, nil},
+		{[]string{"--launch-delay", "600", "--minimized"}, nil},
+		{[]string{"-launch-delay=30", "-minimized=true", "--profile", "work"}, []string{"--profile", "work"}},
+		{[]string{"--profile", "work", "--minimized", "--theme=dark"}, []string{"--profile", "work", "--theme=dark"}},
+		{[]string{"--minimized", "import-config", "--minimized"}, []string{"import-config", "--minimized"}},
+		{[]string{"--", "--launch-delay", "5"}, []string{"--", "--launch-delay", "5"}},
+	}
+
+	for _, tt := range tests {
+		got := restartArgs(tt.args)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("restartArgs(%q) = %q, want %q", tt.args, got, tt.want)
+			continue
+		}
+
+		options, err := ParseLaunchOptions(got, io.Discard)
+		if err != nil {
+			t.Errorf("restart arguments %q do not parse: %v", got, err)
+			continue
+		}
+		if options.LaunchDelay != 0 || options.Minimized {
+			t.Errorf("restart arguments %q still delay or minimise the app", got)
+		}
+	}
+}
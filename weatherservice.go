@@ -4,15 +4,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"net/url"
+	"sync"
 	"time"
 )
 
+// weatherHTTPClient fetches geocoding and forecast data
+var weatherHTTPClient = newHTTPClient(30 * time.Second)
+
 // WeatherService handles weather-related operations
 type WeatherService struct {
-	app            *App
-	trayUpdateFunc func(*WeatherData)
+	app              *App
+	trayUpdateFunc   func(*WeatherData)
+	refreshStateFunc func(refreshing bool)
+
+	mu          sync.Mutex
+	lastWeather *WeatherData
 }
 
 // WeatherData represents the weather information
@@ -20,7 +27,12 @@ type WeatherData struct {
 	Location    string        `json:"location"`
 	Temperature float64       `json:"temperature"`
 	FeelsLike   float64       `json:"feelsLike"`
+	TodayMax    float64       `json:"todayMax"`
+	TodayMin    float64       `json:"todayMin"`
+	RainChance  int           `json:"rainChance"` // next hour, in percent
 	Condition   string        `json:"condition"`
+	WeatherCode int           `json:"weatherCode"`
+	IsDay       bool          `json:"isDay"`
 	Description string        `json:"description"`
 	Humidity    int           `json:"humidity"`
 	WindSpeed   float64       `json:"windSpeed"`
@@ -31,12 +43,13 @@ type WeatherData struct {
 
 // ForecastDay represents a single day forecast
 type ForecastDay struct {
-	Date      string  `json:"date"`
-	DayOfWeek string  `json:"dayOfWeek"`
-	MaxTemp   float64 `json:"maxTemp"`
-	MinTemp   float64 `json:"minTemp"`
-	Condition string  `json:"condition"`
-	Icon      string  `json:"icon"`
+	Date        string  `json:"date"`
+	DayOfWeek   string  `json:"dayOfWeek"`
+	MaxTemp     float64 `json:"maxTemp"`
+	MinTemp     float64 `json:"minTemp"`
+	Condition   string  `json:"condition"`
+	WeatherCode int     `json:"weatherCode"`
+	Icon        string  `json:"icon"`
 }
 
 // NewWeatherService creates a new weather service instance
@@ -49,6 +62,19 @@ func (w *WeatherService) SetTrayUpdateFunc(updateFunc func(*WeatherData)) {
 	w.trayUpdateFunc = updateFunc
 }
 
+// SetRefreshStateFunc sets the function told when a weather fetch starts and
+// ends. It must not block.
+func (w *WeatherService) SetRefreshStateFunc(stateFunc func(refreshing bool)) {
+	w.refreshStateFunc = stateFunc
+}
+
+// setRefreshing reports the refresh state to the refresh state function
+func (w *WeatherService) setRefreshing(refreshing bool) {
+	if w.refreshStateFunc != nil {
+		w.refreshStateFunc(refreshing)
+	}
+}
+
 // GeocodingResult represents geocoding API response
 type GeocodingResult struct {
 	Results []struct {
@@ -67,7 +93,12 @@ type OpenMeteoResponse struct {
 		ApparentTemp     float64 `json:"apparent_temperature"`
 		WindSpeed        float64 `json:"wind_speed_10m"`
 		WeatherCode      int     `json:"weather_code"`
+		IsDay            int     `json:"is_day"`
 	} `json:"current"`
+	Hourly struct {
+		Time                     []string `json:"time"`
+		PrecipitationProbability []int    `json:"precipitation_probability"`
+	} `json:"hourly"`
 	Daily struct {
 		Time        []string  `json:"time"`
 		TempMax     []float64 `json:"temperature_2m_max"`
@@ -76,16 +107,17 @@ type OpenMeteoResponse struct {
 	} `json:"daily"`
 }
 
-// getCoordinates geocodes a location name to coordinates
-func (w *WeatherService) getCoordinates(location string) (float64, float64, error) {
+// getCoordinates geocodes a location name to coordinates, matching names in
+// the given language
+func (w *WeatherService) getCoordinates(location, lang string) (float64, float64, error) {
 	baseURL := "https://geocoding-api.open-meteo.com/v1/search"
 	params := url.Values{}
 	params.Add("name", location)
 	params.Add("count", "1")
-	params.Add("language", "en")
+	params.Add("language", normalizeLanguage(lang))
 	params.Add("format", "json")
 
-	resp, err := http.Get(baseURL + "?" + params.Encode())
+	resp, err := weatherHTTPClient.Get(baseURL + "?" + params.Encode())
 	if err != nil {
 		return 0, 0, err
 	}
@@ -108,43 +140,72 @@ func (w *WeatherService) getCoordinates(location string) (float64, float64, erro
 	return result.Results[0].Latitude, result.Results[0].Longitude, nil
 }
 
-// weatherCodeToCondition converts Open-Meteo weather code to condition string and icon
-func weatherCodeToCondition(code int) (string, string) {
+// weatherCodeToCondition converts Open-Meteo weather code to a localized
+// condition string and icon
+func weatherCodeToCondition(code int, lang string) (string, string) {
+	key, icon := weatherCodeToConditionKey(code)
+	return translate(lang, key), icon
+}
+
+// weatherCodeToConditionKey converts Open-Meteo weather code to a condition
+// message ID and icon
+func weatherCodeToConditionKey(code int) (string, string) {
 	switch code {
 	case 0:
-		return "Clear Sky", "100"
+		return "condition.clearSky", "100"
 	case 1, 2, 3:
-		return "Partly Cloudy", "101"
+		return "condition.partlyCloudy", "101"
 	case 45, 48:
-		return "Foggy", "500"
+		return "condition.foggy", "500"
 	case 51, 53, 55:
-		return "Drizzle", "300"
+		return "condition.drizzle", "300"
 	case 61, 63, 65:
-		return "Rainy", "305"
+		return "condition.rainy", "305"
 	case 66, 67:
-		return "Freezing Rain", "313"
+		return "condition.freezingRain", "313"
 	case 71, 73, 75:
-		return "Snowy", "400"
+		return "condition.snowy", "400"
 	case 77:
-		return "Snow Grains", "400"
+		return "condition.snowGrains", "400"
 	case 80, 81, 82:
-		return "Rain Showers", "309"
+		return "condition.rainShowers", "309"
 	case 85, 86:
-		return "Snow Showers", "404"
+		return "condition.snowShowers", "404"
 	case 95:
-		return "Thunderstorm", "302"
+		return "condition.thunderstorm", "302"
 	case 96, 99:
-		return "Thunderstorm with Hail", "302"
+		return "condition.thunderstormHail", "302"
 	default:
-		return "Unknown", "999"
+		return "condition.unknown", "999"
+	}
+}
+
+// nightIcon returns the night variant of a daytime icon where one exists
+func nightIcon(icon string) string {
+	switch icon {
+	case "100":
+		return "150"
+	case "101":
+		return "151"
+	case "309":
+		return "350"
+	case "404":
+		return "456"
+	default:
+		return icon
 	}
 }
 
 // GetWeather fetches weather data for a given location from Open-Meteo API
 func (w *WeatherService) GetWeather(location string) (*WeatherData, error) {
+	lang := defaultLanguage
+	config, err := w.app.LoadConfig()
+	if err == nil {
+		lang = config.Language
+	}
+
 	// If location is empty, use default from config
 	if location == "" {
-		config, err := w.app.LoadConfig()
 		if err == nil && config.CustomSettings["weatherLocation"] != nil {
 			location = config.CustomSettings["weatherLocation"].(string)
 		} else {
@@ -152,8 +213,11 @@ func (w *WeatherService) GetWeather(location string) (*WeatherData, error) {
 		}
 	}
 
+	w.setRefreshing(true)
+	defer w.setRefreshing(false)
+
 	// Get coordinates for location
-	lat, lon, err := w.getCoordinates(location)
+	lat, lon, err := w.getCoordinates(location, lang)
 	if err != nil {
 		return nil, fmt.Errorf("failed to geocode location: %w", err)
 	}
@@ -163,12 +227,14 @@ func (w *WeatherService) GetWeather(location string) (*WeatherData, error) {
 	params := url.Values{}
 	params.Add("latitude", fmt.Sprintf("%.4f", lat))
 	params.Add("longitude", fmt.Sprintf("%.4f", lon))
-	params.Add("current", "temperature_2m,relative_humidity_2m,apparent_temperature,weather_code,wind_speed_10m")
+	params.Add("current", "temperature_2m,relative_humidity_2m,apparent_temperature,weather_code,wind_speed_10m,is_day")
 	params.Add("daily", "weather_code,temperature_2m_max,temperature_2m_min")
+	params.Add("hourly", "precipitation_probability")
+	params.Add("forecast_hours", "2")
 	params.Add("timezone", "auto")
 	params.Add("forecast_days", "6")
 
-	resp, err := http.Get(baseURL + "?" + params.Encode())
+	resp, err := weatherHTTPClient.Get(baseURL + "?" + params.Encode())
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch weather: %w", err)
 	}
@@ -185,14 +251,20 @@ func (w *WeatherService) GetWeather(location string) (*WeatherData, error) {
 	}
 
 	// Convert to our weather data structure
-	condition, icon := weatherCodeToCondition(apiResp.Current.WeatherCode)
+	condition, icon := weatherCodeToCondition(apiResp.Current.WeatherCode, lang)
+	isDay := apiResp.Current.IsDay == 1
+	if !isDay {
+		icon = nightIcon(icon)
+	}
 
 	weather := &WeatherData{
 		Location:    location,
 		Temperature: apiResp.Current.Temperature,
 		FeelsLike:   apiResp.Current.ApparentTemp,
 		Condition:   condition,
-		Description: fmt.Sprintf("%s in %s", condition, location),
+		WeatherCode: apiResp.Current.WeatherCode,
+		IsDay:       isDay,
+		Description: translate(lang, "weather.description", condition, location),
 		Humidity:    apiResp.Current.RelativeHumidity,
 		WindSpeed:   apiResp.Current.WindSpeed,
 		Icon:        icon,
@@ -200,28 +272,57 @@ func (w *WeatherService) GetWeather(location string) (*WeatherData, error) {
 		Forecast:    make([]ForecastDay, 0),
 	}
 
+	if len(apiResp.Daily.Time) > 0 {
+		weather.TodayMax = apiResp.Daily.TempMax[0]
+		weather.TodayMin = apiResp.Daily.TempMin[0]
+	}
+	// The first hourly value is the current hour, the second the next one
+	if chances := apiResp.Hourly.PrecipitationProbability; len(chances) > 1 {
+		weather.RainChance = chances[1]
+	} else if len(chances) == 1 {
+		weather.RainChance = chances[0]
+	}
+
 	// Build forecast (skip today, get next 5 days)
 	for i := 1; i < len(apiResp.Daily.Time) && i <= 5; i++ {
 		date, _ := time.Parse("2006-01-02", apiResp.Daily.Time[i])
-		condition, icon := weatherCodeToCondition(apiResp.Daily.WeatherCode[i])
+		condition, icon := weatherCodeToCondition(apiResp.Daily.WeatherCode[i], lang)
 
 		forecast := ForecastDay{
-			Date:      apiResp.Daily.Time[i],
-			DayOfWeek: date.Format("Monday"),
-			MaxTemp:   apiResp.Daily.TempMax[i],
-			MinTemp:   apiResp.Daily.TempMin[i],
-			Condition: condition,
-			Icon:      icon,
+			Date:        apiResp.Daily.Time[i],
+			DayOfWeek:   weekdayName(date.Weekday(), lang),
+			MaxTemp:     apiResp.Daily.TempMax[i],
+			MinTemp:     apiResp.Daily.TempMin[i],
+			Condition:   condition,
+			WeatherCode: apiResp.Daily.WeatherCode[i],
+			Icon:        icon,
 		}
 		weather.Forecast = append(weather.Forecast, forecast)
 	}
 
+	w.mu.Lock()
+	w.lastWeather = weather
+	w.mu.Unlock()
+
 	return weather, nil
 }
 
+// latestWeather returns the most recently fetched weather, fetching it if
+// there is none yet
+func (w *WeatherService) latestWeather() (*WeatherData, error) {
+	w.mu.Lock()
+	weather := w.lastWeather
+	w.mu.Unlock()
+
+	if weather != nil {
+		return weather, nil
+	}
+	return w.GetWeather("")
+}
+
 // UpdateLocation updates the weather location in config
 func (w *WeatherService) UpdateLocation(location string) error {
-	config, err := w.app.LoadConfig()
+	config, err := w.app.loadStoredConfig()
 	if err != nil {
 		return err
 	}
@@ -277,3 +378,33 @@ func (w *WeatherService) GetStoredLocation() (string, error) {
 
 	return "New York", nil
 }
+
+// Temperature units accepted by the temperatureUnit setting
+const (
+	TemperatureUnitCelsius    = "celsius"
+	TemperatureUnitFahrenheit = "fahrenheit"
+)
+
+// temperatureUnit returns the configured temperature unit
+func temperatureUnit(config *AppConfig) string {
+	if unit, ok := config.CustomSettings["temperatureUnit"].(string); ok && unit == TemperatureUnitFahrenheit {
+		return TemperatureUnitFahrenheit
+	}
+	return TemperatureUnitCelsius
+}
+
+// convertTemperature converts a Celsius temperature to the given unit
+func convertTemperature(celsius float64, unit string) float64 {
+	if unit == TemperatureUnitFahrenheit {
+		return celsius*9/5 + 32
+	}
+	return celsius
+}
+
+// temperatureSymbol returns the degree symbol for the given unit
+func temperatureSymbol(unit string) string {
+	if unit == TemperatureUnitFahrenheit {
+		return "°F"
+	}
+	return "°C"
+}
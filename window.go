@@ -0,0 +1,228 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
+)
+
+// WindowPosition is a remembered window position on one screen
+type WindowPosition struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Appearance is the resolved theme and language sent to the frontend
+type Appearance struct {
+	Theme    string `json:"theme"`
+	Language string `json:"language"`
+}
+
+// windowPositionSaveDelay debounces saving the position while dragging
+const windowPositionSaveDelay = 500 * time.Millisecond
+
+// windowState tracks pending window position saves
+type windowState struct {
+	mu        sync.Mutex
+	saveTimer *time.Timer
+	// placed is where the app last positioned the window itself
+	placed *WindowPosition
+}
+
+// windowsTheme maps a configured theme to the Windows window theme
+func windowsTheme(theme string) application.Theme {
+	switch theme {
+	case "dark":
+		return application.Dark
+	case "light":
+		return application.Light
+	default:
+		return application.SystemDefault
+	}
+}
+
+// macAppearance maps a configured theme to the macOS window appearance
+func macAppearance(theme string) application.MacAppearanceType {
+	switch theme {
+	case "dark":
+		return application.NSAppearanceNameDarkAqua
+	case "light":
+		return application.NSAppearanceNameAqua
+	default:
+		return application.DefaultAppearance
+	}
+}
+
+// resolveTheme turns the "system" theme into "light" or "dark"
+func resolveTheme(theme string) string {
+	if theme != "system" {
+		return theme
+	}
+
+	if app := application.Get(); app != nil && app.Env.IsDarkMode() {
+		return "dark"
+	}
+	return "light"
+}
+
+// mainWindowOptions builds the main window options from the configuration
+func mainWindowOptions(config *AppConfig) application.WebviewWindowOptions {
+	return application.WebviewWindowOptions{
+		Title: "Weather App",
+		Mac: application.MacWindow{
+			InvisibleTitleBarHeight: 50,
+			Backdrop:                application.MacBackdropTranslucent,
+			TitleBar:                application.MacTitleBarHiddenInset,
+			Appearance:              macAppearance(config.Theme),
+		},
+		Windows: application.WindowsWindow{
+			Theme: windowsTheme(config.Theme),
+		},
+		Width:            config.WindowWidth,
+		Height:           config.WindowHeight,
+		MinWidth:         config.WindowWidth,
+		MinHeight:        config.WindowHeight,
+		MaxWidth:         config.WindowWidth,
+		MaxHeight:        config.WindowHeight,
+		BackgroundColour: application.NewRGBA(0, 0, 0, 0),
+		URL:              "/",
+		Hidden:           true, // Start hidden
+		Frameless:        true,
+	}
+}
+
+// GetAppearance returns the resolved theme and language for the frontend
+func (a *App) GetAppearance() (*Appearance, error) {
+	config, err := a.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Appearance{
+		Theme:    resolveTheme(config.Theme),
+		Language: config.Language,
+	}, nil
+}
+
+// applyWindowConfig applies size, theme and language changes to the running
+// window
+func (a *App) applyWindowConfig(config *AppConfig) {
+	if a.mainWindow == nil {
+		return
+	}
+
+	width, height := a.mainWindow.Size()
+	if width != config.WindowWidth || height != config.WindowHeight {
+		// Widen the limits first so the new size is never clamped
+		a.mainWindow.SetMinSize(0, 0)
+		a.mainWindow.SetMaxSize(0, 0)
+		a.mainWindow.SetSize(config.WindowWidth, config.WindowHeight)
+		a.mainWindow.SetMinSize(config.WindowWidth, config.WindowHeight)
+		a.mainWindow.SetMaxSize(config.WindowWidth, config.WindowHeight)
+	}
+
+	a.emitAppearance(config)
+}
+
+// emitAppearance notifies the frontend of the current theme and language
+func (a *App) emitAppearance(config *AppConfig) {
+	if a.mainWindow == nil {
+		return
+	}
+
+	a.mainWindow.EmitEvent("appearanceChanged", &Appearance{
+		Theme:    resolveTheme(config.Theme),
+		Language: config.Language,
+	})
+}
+
+// attachWindowHandlers wires the main window to remember its position and
+// follow system theme changes
+func (a *App) attachWindowHandlers(app *application.App) {
+	if a.mainWindow == nil {
+		return
+	}
+
+	a.mainWindow.OnWindowEvent(events.Common.WindowDidMove, func(event *application.WindowEvent) {
+		a.scheduleWindowPositionSave()
+	})
+
+	app.Event.OnApplicationEvent(events.Common.ThemeChanged, func(event *application.ApplicationEvent) {
+		config, err := a.LoadConfig()
+		if err == nil && config.Theme == "system" {
+			a.emitAppearance(config)
+		}
+	})
+}
+
+// placeWindow moves the window and records the position, so the move event
+// it causes is not remembered as the user's choice
+func (a *App) placeWindow(x, y int) {
+	a.window.mu.Lock()
+	a.window.placed = &WindowPosition{X: x, Y: y}
+	a.window.mu.Unlock()
+
+	a.mainWindow.SetPosition(x, y)
+}
+
+// scheduleWindowPositionSave saves the window position once it stops moving
+func (a *App) scheduleWindowPositionSave() {
+	a.window.mu.Lock()
+	defer a.window.mu.Unlock()
+
+	if a.window.saveTimer != nil {
+		a.window.saveTimer.Stop()
+	}
+	a.window.saveTimer = time.AfterFunc(windowPositionSaveDelay, a.saveWindowPosition)
+}
+
+// saveWindowPosition stores the window position for the screen it is on,
+// unless the window is where the app placed it
+func (a *App) saveWindowPosition() {
+	if a.mainWindow == nil || !a.mainWindow.IsVisible() {
+		return
+	}
+
+	screen, err := a.mainWindow.GetScreen()
+	if err != nil || screen == nil {
+		return
+	}
+
+	x, y := a.mainWindow.Position()
+	position := WindowPosition{X: x, Y: y}
+
+	a.window.mu.Lock()
+	placed := a.window.placed != nil && *a.window.placed == position
+	a.window.mu.Unlock()
+	if placed {
+		return
+	}
+
+	err = a.updateStoredConfig(func(config *AppConfig) (bool, error) {
+		if config.WindowPositions[screen.ID] == position {
+			return false, nil
+		}
+		if config.WindowPositions == nil {
+			config.WindowPositions = make(map[string]WindowPosition)
+		}
+		config.WindowPositions[screen.ID] = position
+		return true, nil
+	})
+	if err != nil {
+		log.Printf("Failed to save window position: %v", err)
+	}
+}
+
+// rememberedWindowPosition returns the saved position for a screen
+func (a *App) rememberedWindowPosition(screen *application.Screen) (WindowPosition, bool) {
+	config, err := a.LoadConfig()
+	if err != nil {
+		return WindowPosition{}, false
+	}
+
+	position, ok := config.WindowPositions[screen.ID]
+	return position, ok
+}